@@ -0,0 +1,219 @@
+// Package builder composes SQL from a tree of conditions instead of string
+// concatenation, in the style of xorm's builder package, so that a caller
+// (typically an LLM) can describe a query structurally and get back SQL
+// that is provably well-formed and parameterized.
+package builder
+
+import "fmt"
+
+// Cond is a single node in a WHERE-clause condition tree. Writing a Cond
+// appends its SQL fragment to w.SQL and its bind values to w.Args.
+type Cond interface {
+	WriteTo(w *Writer) error
+}
+
+// condList is the shared implementation behind And/Or.
+type condList struct {
+	op    string
+	conds []Cond
+}
+
+func (c *condList) WriteTo(w *Writer) error {
+	if len(c.conds) == 0 {
+		return nil
+	}
+	if len(c.conds) == 1 {
+		return c.conds[0].WriteTo(w)
+	}
+
+	w.SQL.WriteByte('(')
+	for i, cond := range c.conds {
+		if i > 0 {
+			w.SQL.WriteString(c.op)
+		}
+		if err := cond.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	w.SQL.WriteByte(')')
+	return nil
+}
+
+// And combines conditions with AND.
+func And(conds ...Cond) Cond { return &condList{op: " AND ", conds: conds} }
+
+// Or combines conditions with OR.
+func Or(conds ...Cond) Cond { return &condList{op: " OR ", conds: conds} }
+
+// Not negates a single condition.
+type notCond struct{ cond Cond }
+
+func Not(cond Cond) Cond { return &notCond{cond} }
+
+func (c *notCond) WriteTo(w *Writer) error {
+	w.SQL.WriteString("NOT (")
+	if err := c.cond.WriteTo(w); err != nil {
+		return err
+	}
+	w.SQL.WriteByte(')')
+	return nil
+}
+
+// compareCond implements the column-op-value conditions: Eq, Neq, Like,
+// Between (which takes two values).
+type compareCond struct {
+	col    string
+	op     string
+	values []interface{}
+}
+
+func (c *compareCond) WriteTo(w *Writer) error {
+	switch c.op {
+	case "BETWEEN":
+		if len(c.values) != 2 {
+			return fmt.Errorf("builder: BETWEEN requires exactly 2 values, got %d", len(c.values))
+		}
+		w.WriteIdent(c.col)
+		w.SQL.WriteString(" BETWEEN ")
+		w.WritePlaceholder()
+		w.Args = append(w.Args, c.values[0])
+		w.SQL.WriteString(" AND ")
+		w.WritePlaceholder()
+		w.Args = append(w.Args, c.values[1])
+	default:
+		w.WriteIdent(c.col)
+		w.SQL.WriteString(" " + c.op + " ")
+		w.WritePlaceholder()
+		w.Args = append(w.Args, c.values[0])
+	}
+	return nil
+}
+
+// Eq builds `col = ?`.
+func Eq(col string, value interface{}) Cond { return &compareCond{col: col, op: "=", values: []interface{}{value}} }
+
+// Neq builds `col <> ?`.
+func Neq(col string, value interface{}) Cond { return &compareCond{col: col, op: "<>", values: []interface{}{value}} }
+
+// Like builds `col LIKE ?`.
+func Like(col string, pattern string) Cond {
+	return &compareCond{col: col, op: "LIKE", values: []interface{}{pattern}}
+}
+
+// Between builds `col BETWEEN ? AND ?`.
+func Between(col string, lo, hi interface{}) Cond {
+	return &compareCond{col: col, op: "BETWEEN", values: []interface{}{lo, hi}}
+}
+
+// inCond implements In/NotIn.
+type inCond struct {
+	col    string
+	not    bool
+	values []interface{}
+}
+
+func (c *inCond) WriteTo(w *Writer) error {
+	if len(c.values) == 0 {
+		// An empty IN-list matches nothing (or everything, for NOT IN); make
+		// that explicit rather than emitting invalid SQL like "IN ()".
+		if c.not {
+			w.SQL.WriteString("1=1")
+		} else {
+			w.SQL.WriteString("1=0")
+		}
+		return nil
+	}
+
+	w.WriteIdent(c.col)
+	if c.not {
+		w.SQL.WriteString(" NOT IN (")
+	} else {
+		w.SQL.WriteString(" IN (")
+	}
+	for i, v := range c.values {
+		if i > 0 {
+			w.SQL.WriteString(", ")
+		}
+		w.WritePlaceholder()
+		w.Args = append(w.Args, v)
+	}
+	w.SQL.WriteByte(')')
+	return nil
+}
+
+// In builds `col IN (?, ?, ...)`.
+func In(col string, values ...interface{}) Cond { return &inCond{col: col, values: values} }
+
+// NotIn builds `col NOT IN (?, ?, ...)`.
+func NotIn(col string, values ...interface{}) Cond { return &inCond{col: col, not: true, values: values} }
+
+// inSelectCond implements In/NotIn against a sub-query instead of a
+// literal value list.
+type inSelectCond struct {
+	col string
+	not bool
+	sub *SelectBuilder
+}
+
+// InSelect builds `col IN (<sub-query>)`.
+func InSelect(col string, sub *SelectBuilder) Cond { return &inSelectCond{col: col, sub: sub} }
+
+// NotInSelect builds `col NOT IN (<sub-query>)`.
+func NotInSelect(col string, sub *SelectBuilder) Cond {
+	return &inSelectCond{col: col, not: true, sub: sub}
+}
+
+func (c *inSelectCond) WriteTo(w *Writer) error {
+	subSQL, subArgs, err := c.sub.ToSQL(w.Dialect)
+	if err != nil {
+		return err
+	}
+
+	w.WriteIdent(c.col)
+	if c.not {
+		w.SQL.WriteString(" NOT IN (")
+	} else {
+		w.SQL.WriteString(" IN (")
+	}
+	w.SQL.WriteString(subSQL)
+	w.SQL.WriteByte(')')
+	w.Args = append(w.Args, subArgs...)
+	return nil
+}
+
+// isNullCond implements IsNull.
+type isNullCond struct {
+	col string
+	not bool
+}
+
+func (c *isNullCond) WriteTo(w *Writer) error {
+	w.WriteIdent(c.col)
+	if c.not {
+		w.SQL.WriteString(" IS NOT NULL")
+	} else {
+		w.SQL.WriteString(" IS NULL")
+	}
+	return nil
+}
+
+// IsNull builds `col IS NULL`.
+func IsNull(col string) Cond { return &isNullCond{col: col} }
+
+// IsNotNull builds `col IS NOT NULL`.
+func IsNotNull(col string) Cond { return &isNullCond{col: col, not: true} }
+
+// Expr injects a pre-validated raw SQL fragment with its own bind values,
+// for conditions the Cond tree doesn't otherwise express.
+type exprCond struct {
+	sql  string
+	args []interface{}
+}
+
+func Expr(sql string, args ...interface{}) Cond { return &exprCond{sql: sql, args: args} }
+
+func (c *exprCond) WriteTo(w *Writer) error {
+	w.SQL.WriteString(c.sql)
+	w.Args = append(w.Args, c.args...)
+	return nil
+}
@@ -0,0 +1,49 @@
+package builder
+
+import "fmt"
+
+// DeleteBuilder builds a DELETE statement from validated identifiers and a
+// Cond tree. As with UpdateBuilder, a WHERE clause is mandatory so an
+// unfiltered DELETE can't be constructed by accident.
+type DeleteBuilder struct {
+	schema string
+	table  string
+	where  Cond
+}
+
+// Delete starts a DeleteBuilder for the given schema-qualified table.
+func Delete(schema, table string) *DeleteBuilder {
+	return &DeleteBuilder{schema: schema, table: table}
+}
+
+// Where sets the required WHERE condition.
+func (b *DeleteBuilder) Where(cond Cond) *DeleteBuilder {
+	b.where = cond
+	return b
+}
+
+// ToSQL renders the builder to a parameterized SQL string (with `?`
+// placeholders) and its bind values.
+func (b *DeleteBuilder) ToSQL(dialect Dialect) (string, []interface{}, error) {
+	if b.table == "" {
+		return "", nil, fmt.Errorf("builder: Delete(schema, table) is required")
+	}
+	if b.where == nil {
+		return "", nil, fmt.Errorf("builder: Where(cond) is required for DELETE")
+	}
+
+	w := NewWriter(dialect)
+	w.SQL.WriteString("DELETE FROM ")
+	if b.schema != "" {
+		w.WriteIdent(b.schema)
+		w.SQL.WriteByte('.')
+	}
+	w.WriteIdent(b.table)
+
+	w.SQL.WriteString(" WHERE ")
+	if err := b.where.WriteTo(w); err != nil {
+		return "", nil, err
+	}
+
+	return w.SQL.String(), w.Args, nil
+}
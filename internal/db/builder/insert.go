@@ -0,0 +1,62 @@
+package builder
+
+import "fmt"
+
+// InsertBuilder builds a single-row INSERT statement from validated
+// identifiers, rather than raw string concatenation.
+type InsertBuilder struct {
+	schema  string
+	table   string
+	columns []string
+	values  []interface{}
+}
+
+// Insert starts an InsertBuilder for the given schema-qualified table.
+func Insert(schema, table string) *InsertBuilder {
+	return &InsertBuilder{schema: schema, table: table}
+}
+
+// Set adds a column/value pair to the row being inserted.
+func (b *InsertBuilder) Set(col string, value interface{}) *InsertBuilder {
+	b.columns = append(b.columns, col)
+	b.values = append(b.values, value)
+	return b
+}
+
+// ToSQL renders the builder to a parameterized SQL string (with `?`
+// placeholders) and its bind values.
+func (b *InsertBuilder) ToSQL(dialect Dialect) (string, []interface{}, error) {
+	if b.table == "" {
+		return "", nil, fmt.Errorf("builder: Insert(schema, table) is required")
+	}
+	if len(b.columns) == 0 {
+		return "", nil, fmt.Errorf("builder: at least one Set(col, value) is required")
+	}
+
+	w := NewWriter(dialect)
+	w.SQL.WriteString("INSERT INTO ")
+	if b.schema != "" {
+		w.WriteIdent(b.schema)
+		w.SQL.WriteByte('.')
+	}
+	w.WriteIdent(b.table)
+
+	w.SQL.WriteString(" (")
+	for i, col := range b.columns {
+		if i > 0 {
+			w.SQL.WriteString(", ")
+		}
+		w.WriteIdent(col)
+	}
+	w.SQL.WriteString(") VALUES (")
+	for i, v := range b.values {
+		if i > 0 {
+			w.SQL.WriteString(", ")
+		}
+		w.WritePlaceholder()
+		w.Args = append(w.Args, v)
+	}
+	w.SQL.WriteByte(')')
+
+	return w.SQL.String(), w.Args, nil
+}
@@ -0,0 +1,182 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OrderBy describes a single ORDER BY term.
+type OrderBy struct {
+	Column string
+	Desc   bool
+}
+
+// joinClause describes a single JOIN against another table.
+type joinClause struct {
+	kind   string // "JOIN", "LEFT JOIN"
+	schema string
+	table  string
+	on     Cond
+}
+
+// SelectBuilder builds a single-table SELECT statement (with optional
+// joins) from validated identifiers and a Cond tree, rather than raw
+// string concatenation.
+type SelectBuilder struct {
+	schema  string
+	table   string
+	columns []string
+	joins   []joinClause
+	where   Cond
+	groupBy []string
+	having  Cond
+	orderBy []OrderBy
+	limit   int
+}
+
+// Select starts a SelectBuilder for the given columns. An empty columns
+// list means SELECT *.
+func Select(columns ...string) *SelectBuilder {
+	return &SelectBuilder{columns: columns}
+}
+
+// From sets the schema-qualified table to select from.
+func (b *SelectBuilder) From(schema, table string) *SelectBuilder {
+	b.schema = schema
+	b.table = table
+	return b
+}
+
+// Join adds an INNER JOIN to schema.table with the given ON condition.
+func (b *SelectBuilder) Join(schema, table string, on Cond) *SelectBuilder {
+	return b.addJoin("JOIN", schema, table, on)
+}
+
+// LeftJoin adds a LEFT JOIN to schema.table with the given ON condition.
+func (b *SelectBuilder) LeftJoin(schema, table string, on Cond) *SelectBuilder {
+	return b.addJoin("LEFT JOIN", schema, table, on)
+}
+
+func (b *SelectBuilder) addJoin(kind, schema, table string, on Cond) *SelectBuilder {
+	b.joins = append(b.joins, joinClause{kind: kind, schema: schema, table: table, on: on})
+	return b
+}
+
+// Where sets the WHERE condition.
+func (b *SelectBuilder) Where(cond Cond) *SelectBuilder {
+	b.where = cond
+	return b
+}
+
+// GroupBy sets the GROUP BY columns.
+func (b *SelectBuilder) GroupBy(columns ...string) *SelectBuilder {
+	b.groupBy = columns
+	return b
+}
+
+// Having sets the HAVING condition, evaluated after GroupBy.
+func (b *SelectBuilder) Having(cond Cond) *SelectBuilder {
+	b.having = cond
+	return b
+}
+
+// OrderBy appends an ORDER BY term.
+func (b *SelectBuilder) OrderBy(column string, desc bool) *SelectBuilder {
+	b.orderBy = append(b.orderBy, OrderBy{Column: column, Desc: desc})
+	return b
+}
+
+// Limit sets the LIMIT clause. Zero means no limit is applied by the
+// builder itself (the caller's LIMIT-injection path still applies).
+func (b *SelectBuilder) Limit(n int) *SelectBuilder {
+	b.limit = n
+	return b
+}
+
+// ToSQL renders the builder to a parameterized SQL string (with `?`
+// placeholders, rebound to the driver's dialect by db.BindParams) and its
+// bind values.
+func (b *SelectBuilder) ToSQL(dialect Dialect) (string, []interface{}, error) {
+	if b.table == "" {
+		return "", nil, fmt.Errorf("builder: From(schema, table) is required")
+	}
+
+	w := NewWriter(dialect)
+	w.SQL.WriteString("SELECT ")
+	if dialect == DialectMSSQL && b.limit > 0 {
+		w.SQL.WriteString(fmt.Sprintf("TOP (%d) ", b.limit))
+	}
+	if len(b.columns) == 0 {
+		w.SQL.WriteByte('*')
+	} else {
+		for i, col := range b.columns {
+			if i > 0 {
+				w.SQL.WriteString(", ")
+			}
+			w.WriteIdent(col)
+		}
+	}
+
+	w.SQL.WriteString(" FROM ")
+	if b.schema != "" {
+		w.WriteIdent(b.schema)
+		w.SQL.WriteByte('.')
+	}
+	w.WriteIdent(b.table)
+
+	for _, j := range b.joins {
+		w.SQL.WriteString(" " + j.kind + " ")
+		if j.schema != "" {
+			w.WriteIdent(j.schema)
+			w.SQL.WriteByte('.')
+		}
+		w.WriteIdent(j.table)
+		w.SQL.WriteString(" ON ")
+		if err := j.on.WriteTo(w); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if b.where != nil {
+		w.SQL.WriteString(" WHERE ")
+		if err := b.where.WriteTo(w); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if len(b.groupBy) > 0 {
+		w.SQL.WriteString(" GROUP BY ")
+		for i, col := range b.groupBy {
+			if i > 0 {
+				w.SQL.WriteString(", ")
+			}
+			w.WriteIdent(col)
+		}
+	}
+
+	if b.having != nil {
+		w.SQL.WriteString(" HAVING ")
+		if err := b.having.WriteTo(w); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if len(b.orderBy) > 0 {
+		w.SQL.WriteString(" ORDER BY ")
+		terms := make([]string, len(b.orderBy))
+		for i, ob := range b.orderBy {
+			term := QuoteIdent(dialect, ob.Column)
+			if ob.Desc {
+				term += " DESC"
+			}
+			terms[i] = term
+		}
+		w.SQL.WriteString(strings.Join(terms, ", "))
+	}
+
+	if b.limit > 0 && dialect != DialectMSSQL {
+		w.SQL.WriteString(fmt.Sprintf(" LIMIT %d", b.limit))
+	}
+
+	return w.SQL.String(), w.Args, nil
+}
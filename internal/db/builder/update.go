@@ -0,0 +1,74 @@
+package builder
+
+import "fmt"
+
+// UpdateBuilder builds an UPDATE statement from validated identifiers and
+// a Cond tree. A WHERE clause is mandatory: an unfiltered UPDATE almost
+// always means a missing condition rather than an intentional full-table
+// write, and the builder is meant to make that mistake impossible rather
+// than just unlikely.
+type UpdateBuilder struct {
+	schema string
+	table  string
+	cols   []string
+	vals   []interface{}
+	where  Cond
+}
+
+// Update starts an UpdateBuilder for the given schema-qualified table.
+func Update(schema, table string) *UpdateBuilder {
+	return &UpdateBuilder{schema: schema, table: table}
+}
+
+// Set adds a column/value assignment to the row(s) being updated.
+func (b *UpdateBuilder) Set(col string, value interface{}) *UpdateBuilder {
+	b.cols = append(b.cols, col)
+	b.vals = append(b.vals, value)
+	return b
+}
+
+// Where sets the required WHERE condition.
+func (b *UpdateBuilder) Where(cond Cond) *UpdateBuilder {
+	b.where = cond
+	return b
+}
+
+// ToSQL renders the builder to a parameterized SQL string (with `?`
+// placeholders) and its bind values.
+func (b *UpdateBuilder) ToSQL(dialect Dialect) (string, []interface{}, error) {
+	if b.table == "" {
+		return "", nil, fmt.Errorf("builder: Update(schema, table) is required")
+	}
+	if len(b.cols) == 0 {
+		return "", nil, fmt.Errorf("builder: at least one Set(col, value) is required")
+	}
+	if b.where == nil {
+		return "", nil, fmt.Errorf("builder: Where(cond) is required for UPDATE")
+	}
+
+	w := NewWriter(dialect)
+	w.SQL.WriteString("UPDATE ")
+	if b.schema != "" {
+		w.WriteIdent(b.schema)
+		w.SQL.WriteByte('.')
+	}
+	w.WriteIdent(b.table)
+
+	w.SQL.WriteString(" SET ")
+	for i, col := range b.cols {
+		if i > 0 {
+			w.SQL.WriteString(", ")
+		}
+		w.WriteIdent(col)
+		w.SQL.WriteString(" = ")
+		w.WritePlaceholder()
+		w.Args = append(w.Args, b.vals[i])
+	}
+
+	w.SQL.WriteString(" WHERE ")
+	if err := b.where.WriteTo(w); err != nil {
+		return "", nil, err
+	}
+
+	return w.SQL.String(), w.Args, nil
+}
@@ -0,0 +1,58 @@
+package builder
+
+import "strings"
+
+// Dialect selects the identifier-quoting and placeholder style a Writer
+// renders SQL in.
+type Dialect int
+
+const (
+	DialectPostgreSQL Dialect = iota
+	DialectClickHouse
+	DialectMySQL
+	DialectMSSQL
+)
+
+// Writer accumulates SQL text and bind values as a Cond tree is rendered,
+// quoting identifiers per dialect so the caller never concatenates a raw
+// column or table name into the query string.
+type Writer struct {
+	Dialect Dialect
+	SQL     strings.Builder
+	Args    []interface{}
+}
+
+// NewWriter creates a Writer for the given dialect.
+func NewWriter(dialect Dialect) *Writer {
+	return &Writer{Dialect: dialect}
+}
+
+// WriteIdent appends a quoted identifier, per the writer's dialect:
+// double quotes for PostgreSQL/ClickHouse, backticks for MySQL, brackets
+// for MSSQL.
+func (w *Writer) WriteIdent(ident string) {
+	w.SQL.WriteString(QuoteIdent(w.Dialect, ident))
+}
+
+// QuoteIdent quotes a single identifier for the given dialect, doubling
+// any embedded quote character of the same kind the identifier is closed
+// with (the standard SQL escape) so a hallucinated or attacker-influenced
+// identifier can't close its quote early and inject SQL.
+func QuoteIdent(dialect Dialect, ident string) string {
+	switch dialect {
+	case DialectMySQL:
+		return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+	case DialectMSSQL:
+		return "[" + strings.ReplaceAll(ident, "]", "]]") + "]"
+	default: // PostgreSQL, ClickHouse
+		return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+	}
+}
+
+// WritePlaceholder appends a bind placeholder. Builders always emit `?`
+// here and let db.BindParams rebind to the driver's native style ($1,
+// @p1, ...) when the query reaches Manager.RunSQL, so Cond implementations
+// don't need to know the final dialect's placeholder syntax.
+func (w *Writer) WritePlaceholder() {
+	w.SQL.WriteByte('?')
+}
@@ -0,0 +1,119 @@
+// Package cache provides a pluggable result cache for Manager's RunSQL,
+// ExplainQuery, and ListSchemas calls, keyed on the query plus a
+// driver-supplied schema fingerprint so a cached entry invalidates itself
+// the moment DDL or heavy DML touches a referenced table, without the
+// cache needing to parse table dependencies out of the query.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Stats holds a cache's lifetime hit/miss/eviction counters plus its
+// current size (MemoryCache: entry count; RedisCache: see its Stats),
+// surfaced by Manager.CacheStats for GetDatabaseInfo and the cache_stats
+// tool.
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Size      int64  `json:"size"`
+}
+
+// Cache is the interface a result cache backend implements. Values are
+// opaque, caller-encoded bytes (Manager stores JSON) so an implementation
+// doesn't need to know about the types it's storing - MemoryCache and
+// RedisCache both satisfy this with very different storage underneath.
+type Cache interface {
+	// Get returns the cached value for key, if present and not expired.
+	Get(key string) ([]byte, bool)
+	// Set stores value under key with the given TTL. A zero TTL means the
+	// entry never expires on its own (it can still be evicted for space).
+	// tags labels the entry for Invalidate, e.g. "db:primary" or
+	// "db:primary:table:users".
+	Set(key string, value []byte, ttl time.Duration, tags []string)
+	// Invalidate removes every cached entry tagged with prefix, or nested
+	// under it ("db:primary" also clears "db:primary:table:users"),
+	// returning how many entries were removed.
+	Invalidate(prefix string) int
+	// Stats returns the cache's lifetime hit/miss/eviction counters and
+	// current size.
+	Stats() Stats
+}
+
+// Mode selects which Cache implementation New constructs.
+type Mode string
+
+const (
+	ModeOff    Mode = "off"
+	ModeMemory Mode = "memory"
+	ModeRedis  Mode = "redis"
+)
+
+// New constructs the Cache implementation selected by mode. ModeOff (or an
+// empty mode) returns a nil Cache and a nil error - callers should treat a
+// nil Cache as "caching disabled" and skip wrapping calls with it, rather
+// than calling through to a no-op implementation on every request.
+// maxEntries bounds MemoryCache's entry count in addition to maxBytes; it
+// has no effect on ModeRedis, which relies on Redis's own memory policy.
+func New(mode Mode, maxBytes int64, maxEntries int, redisAddr string) (Cache, error) {
+	switch mode {
+	case ModeOff, "":
+		return nil, nil
+	case ModeMemory:
+		return NewMemoryCache(maxBytes, maxEntries), nil
+	case ModeRedis:
+		if redisAddr == "" {
+			return nil, fmt.Errorf("cache: CACHE_REDIS_ADDR is required for CACHE_MODE=redis")
+		}
+		return NewRedisCache(redisAddr), nil
+	default:
+		return nil, fmt.Errorf("cache: unknown CACHE_MODE %q", mode)
+	}
+}
+
+// TagPrefix builds the tag Set should attach to a cached entry for
+// database, and Invalidate should be called with to clear it: every entry
+// for database when table is empty, or just the entries touching that one
+// table otherwise. Table name matching is a literal, case-insensitive
+// comparison against whatever identifier followed FROM/JOIN in the
+// original query (e.g. a schema-qualified "public.users" is its own tag,
+// distinct from "users").
+func TagPrefix(database, table string) string {
+	if table == "" {
+		return "db:" + strings.ToLower(database)
+	}
+	return "db:" + strings.ToLower(database) + ":table:" + strings.ToLower(table)
+}
+
+// Key builds a cache key from kind (the call this result came from, e.g.
+// "run_sql", "explain_sql", "list_schemas"), the database name, the bound
+// query, its positional args, and a schema fingerprint, so entries for
+// different calls, databases, or schema states never collide.
+func Key(kind, database, query string, args []interface{}, schemaFingerprint string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", kind, database, normalizeQuery(query))
+	if argBytes, err := json.Marshal(args); err == nil {
+		h.Write(argBytes)
+	}
+	fmt.Fprintf(h, "\x00%s", schemaFingerprint)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizeQuery collapses whitespace so cosmetic differences (extra
+// spaces, newlines, indentation) don't fragment the cache.
+func normalizeQuery(q string) string {
+	return strings.Join(strings.Fields(q), " ")
+}
+
+// tagMatches reports whether tag should be cleared by an Invalidate(prefix)
+// call: either tag is exactly prefix, or prefix names an ancestor of tag
+// ("db:primary" is an ancestor of "db:primary:table:users").
+func tagMatches(tag, prefix string) bool {
+	return tag == prefix || strings.HasPrefix(tag, prefix+":")
+}
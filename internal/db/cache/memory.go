@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MemoryCache is an in-process LRU cache bounded by total value bytes
+// (maxBytes) and entry count (maxEntries), used for CACHE_MODE=memory -
+// the default mode when caching is enabled. Entries past their TTL are
+// treated as misses and evicted lazily on the next Get rather than by a
+// background sweep.
+type MemoryCache struct {
+	maxBytes   int64
+	maxEntries int
+
+	mu    sync.Mutex
+	bytes int64
+	ll    *list.List
+	items map[string]*list.Element
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+type memoryEntry struct {
+	key      string
+	value    []byte
+	tags     []string
+	expireAt time.Time // zero means no expiry
+}
+
+// NewMemoryCache creates a MemoryCache that evicts its least-recently-used
+// entries once the total size of cached values exceeds maxBytes or its
+// entry count exceeds maxEntries. A zero bound disables that dimension of
+// size-based eviction.
+func NewMemoryCache(maxBytes int64, maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxBytes:   maxBytes,
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	entry := el.Value.(*memoryEntry)
+	if !entry.expireAt.IsZero() && time.Now().After(entry.expireAt) {
+		c.removeElement(el)
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	c.hits.Add(1)
+	return entry.value, true
+}
+
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration, tags []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
+
+	if el, ok := c.items[key]; ok {
+		c.bytes -= int64(len(el.Value.(*memoryEntry).value))
+		el.Value = &memoryEntry{key: key, value: value, tags: tags, expireAt: expireAt}
+		c.bytes += int64(len(value))
+		c.ll.MoveToFront(el)
+	} else {
+		el := c.ll.PushFront(&memoryEntry{key: key, value: value, tags: tags, expireAt: expireAt})
+		c.items[key] = el
+		c.bytes += int64(len(value))
+	}
+
+	for (c.maxBytes > 0 && c.bytes > c.maxBytes || c.maxEntries > 0 && c.ll.Len() > c.maxEntries) && c.ll.Len() > 0 {
+		c.evictions.Add(1)
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// Invalidate removes every entry tagged with prefix or nested under it. It
+// scans every entry rather than maintaining a reverse tag index - simple,
+// and fast enough for the entry counts this cache is sized for.
+func (c *MemoryCache) Invalidate(prefix string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var removed int
+	for el := c.ll.Front(); el != nil; {
+		next := el.Next()
+		entry := el.Value.(*memoryEntry)
+		for _, tag := range entry.tags {
+			if tagMatches(tag, prefix) {
+				c.removeElement(el)
+				removed++
+				break
+			}
+		}
+		el = next
+	}
+	return removed
+}
+
+func (c *MemoryCache) Stats() Stats {
+	c.mu.Lock()
+	size := int64(c.ll.Len())
+	c.mu.Unlock()
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      size,
+	}
+}
+
+// removeElement evicts el from the cache. Callers must hold c.mu.
+func (c *MemoryCache) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	entry := el.Value.(*memoryEntry)
+	delete(c.items, entry.key)
+	c.bytes -= int64(len(entry.value))
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisCache is a Cache backed by a Redis server, for CACHE_MODE=redis
+// deployments that want the result cache shared across multiple MCP
+// server instances instead of kept per-process. Tags are tracked as
+// Redis sets ("tag:<tag>" -> member data keys) so Invalidate can find
+// every key carrying a tag without scanning the whole keyspace.
+type RedisCache struct {
+	client *redis.Client
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// NewRedisCache creates a RedisCache connected to addr (host:port).
+func NewRedisCache(addr string) *RedisCache {
+	return &RedisCache{client: redis.NewClient(&redis.Options{Addr: addr})}
+}
+
+func (c *RedisCache) Get(key string) ([]byte, bool) {
+	value, err := c.client.Get(context.Background(), key).Bytes()
+	if err != nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	return value, true
+}
+
+func (c *RedisCache) Set(key string, value []byte, ttl time.Duration, tags []string) {
+	ctx := context.Background()
+	// A Redis TTL of 0 means "no expiry", matching the Cache interface's
+	// contract for a zero duration.
+	c.client.Set(ctx, key, value, ttl)
+	for _, tag := range tags {
+		c.client.SAdd(ctx, "tag:"+tag, key)
+	}
+}
+
+// Invalidate finds every "tag:" set whose name matches prefix (exactly or
+// nested under it), deletes the data keys it names, then deletes the tag
+// set itself.
+func (c *RedisCache) Invalidate(prefix string) int {
+	ctx := context.Background()
+	removed := make(map[string]struct{})
+
+	var cursor uint64
+	for {
+		tagKeys, next, err := c.client.Scan(ctx, cursor, "tag:"+prefix+"*", 100).Result()
+		if err != nil {
+			break
+		}
+
+		for _, tagKey := range tagKeys {
+			if !tagMatches(strings.TrimPrefix(tagKey, "tag:"), prefix) {
+				continue
+			}
+
+			members, err := c.client.SMembers(ctx, tagKey).Result()
+			if err == nil && len(members) > 0 {
+				c.client.Del(ctx, members...)
+				for _, m := range members {
+					removed[m] = struct{}{}
+				}
+			}
+			c.client.Del(ctx, tagKey)
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return len(removed)
+}
+
+// Stats reports Size as the Redis database's DBSIZE, which counts every key
+// in that database, including this cache's tag sets and any keys other
+// clients wrote, not only cached entries. Evictions is not tracked and
+// stays 0.
+func (c *RedisCache) Stats() Stats {
+	size, _ := c.client.DBSize(context.Background()).Result()
+	return Stats{Hits: c.hits.Load(), Misses: c.misses.Load(), Size: size}
+}
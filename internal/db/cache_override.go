@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// CacheOverride lets a single call adjust Manager's result-cache behavior
+// for that call only - e.g. an MCP tool argument asking to bypass the
+// cache or use a shorter TTL than the server default. Attach it to a
+// context with WithCacheOverride before calling into Manager.
+type CacheOverride struct {
+	// Skip disables both reading from and writing to the cache for this
+	// call.
+	Skip bool
+	// TTL, if positive, replaces Manager's configured cache TTL for the
+	// entry this call writes.
+	TTL time.Duration
+}
+
+type cacheOverrideKey struct{}
+
+// WithCacheOverride attaches override to ctx so the next Manager call made
+// with it honors a per-call cache skip or TTL.
+func WithCacheOverride(ctx context.Context, override CacheOverride) context.Context {
+	return context.WithValue(ctx, cacheOverrideKey{}, override)
+}
+
+// CacheOverrideFromContext returns the CacheOverride attached to ctx, if
+// any.
+func CacheOverrideFromContext(ctx context.Context) (CacheOverride, bool) {
+	override, ok := ctx.Value(cacheOverrideKey{}).(CacheOverride)
+	return override, ok
+}
@@ -7,9 +7,11 @@ import (
 	"strings"
 	"time"
 
+	"database-mcp/internal/safety/estimator"
 	"database-mcp/internal/types"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 )
 
 // ClickHouseDriver implements DatabaseDriver for ClickHouse
@@ -281,8 +283,9 @@ func (d *ClickHouseDriver) DescribeTable(ctx context.Context, schema, table stri
 	return &desc, nil
 }
 
-// RunSQL executes a SQL query on ClickHouse
-func (d *ClickHouseDriver) RunSQL(ctx context.Context, query string, limit int) (*types.QueryResult, error) {
+// RunSQL executes a SQL query on ClickHouse, binding params as variadic
+// query arguments.
+func (d *ClickHouseDriver) RunSQL(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
 	if d.conn == nil {
 		return nil, fmt.Errorf("ClickHouse not connected")
 	}
@@ -290,11 +293,9 @@ func (d *ClickHouseDriver) RunSQL(ctx context.Context, query string, limit int)
 	startTime := time.Now()
 
 	// Add LIMIT if not present (basic implementation)
-	if limit > 0 && !strings.Contains(strings.ToUpper(query), " LIMIT ") {
-		query = fmt.Sprintf("%s LIMIT %d", strings.TrimRight(query, ";"), limit)
-	}
+	query = injectLimit(query, limit)
 
-	rows, err := d.conn.Query(ctx, query)
+	rows, err := d.conn.Query(ctx, query, params...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -339,6 +340,73 @@ func (d *ClickHouseDriver) RunSQL(ctx context.Context, query string, limit int)
 	}, nil
 }
 
+// RunSQLReadOnly behaves like RunSQL, but sets ClickHouse's `readonly=1`
+// query setting on the context, so the server itself refuses any write the
+// query classifier missed.
+func (d *ClickHouseDriver) RunSQLReadOnly(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
+	roCtx := clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{"readonly": 1}))
+	return d.RunSQL(roCtx, query, params, limit)
+}
+
+// BeginReadOnlySnapshot returns a fixed-settings session rather than an
+// open transaction: ClickHouse's MergeTree engines have no cross-statement
+// snapshot isolation to hold open, so this is a documented no-op beyond
+// pinning `readonly=1` (and, if ctx carries a deadline, `max_execution_time`)
+// on every call the session makes, consistent for the life of the bundle.
+func (d *ClickHouseDriver) BeginReadOnlySnapshot(ctx context.Context) (ReadSession, error) {
+	if d.conn == nil {
+		return nil, fmt.Errorf("ClickHouse not connected")
+	}
+
+	settings := clickhouse.Settings{"readonly": 1}
+	if deadline, ok := ctx.Deadline(); ok {
+		if ms := time.Until(deadline).Milliseconds(); ms > 0 {
+			settings["max_execution_time"] = float64(ms) / 1000
+		}
+	}
+
+	return &clickhouseReadSession{driver: d, roCtx: clickhouse.Context(ctx, clickhouse.WithSettings(settings))}, nil
+}
+
+// WithAdvisoryLock just runs fn: ClickHouse has no cross-session locking
+// primitive to serialize against.
+func (d *ClickHouseDriver) WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// clickhouseReadSession implements ReadSession by delegating straight back
+// to ClickHouseDriver with a fixed, readonly-pinned context - see
+// BeginReadOnlySnapshot.
+type clickhouseReadSession struct {
+	driver *ClickHouseDriver
+	roCtx  context.Context
+}
+
+func (s *clickhouseReadSession) ListSchemas(ctx context.Context) ([]types.Schema, error) {
+	return s.driver.ListSchemas(s.roCtx)
+}
+
+func (s *clickhouseReadSession) ListTables(ctx context.Context, schema string) ([]types.Table, error) {
+	return s.driver.ListTables(s.roCtx, schema)
+}
+
+func (s *clickhouseReadSession) DescribeTable(ctx context.Context, schema, table string) (*types.TableDescription, error) {
+	return s.driver.DescribeTable(s.roCtx, schema, table)
+}
+
+func (s *clickhouseReadSession) RunSQL(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
+	return s.driver.RunSQL(s.roCtx, query, params, limit)
+}
+
+func (s *clickhouseReadSession) ExplainQuery(ctx context.Context, query string) (*types.ExplainResult, error) {
+	return s.driver.ExplainQuery(s.roCtx, query)
+}
+
+// Close is a no-op: there is no transaction to roll back.
+func (s *clickhouseReadSession) Close(ctx context.Context) error {
+	return nil
+}
+
 // ExplainQuery explains a SQL query on ClickHouse
 func (d *ClickHouseDriver) ExplainQuery(ctx context.Context, query string) (*types.ExplainResult, error) {
 	if d.conn == nil {
@@ -388,6 +456,168 @@ func (d *ClickHouseDriver) ExplainQuery(ctx context.Context, query string) (*typ
 	}, nil
 }
 
+// RunSQLStream executes query on ClickHouse and returns a RowIterator that
+// scans rows on demand instead of buffering the whole result set, so a
+// multi-GB answer doesn't have to fit in memory before the first batch is
+// returned.
+func (d *ClickHouseDriver) RunSQLStream(ctx context.Context, query string, params []interface{}, limit int) (RowIterator, error) {
+	if d.conn == nil {
+		return nil, fmt.Errorf("ClickHouse not connected")
+	}
+
+	query = injectLimit(query, limit)
+
+	rows, err := d.conn.Query(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	columnTypes := rows.ColumnTypes()
+	columns := make([]string, len(columnTypes))
+	for i, ct := range columnTypes {
+		columns[i] = ct.Name()
+	}
+
+	return &clickHouseRowIterator{rows: rows, columns: columns}, nil
+}
+
+// RunSQLStreamReadOnly behaves like RunSQLStream, but sets ClickHouse's
+// `readonly=1` query setting on the context, so the server itself refuses
+// any write the query classifier missed.
+func (d *ClickHouseDriver) RunSQLStreamReadOnly(ctx context.Context, query string, params []interface{}, limit int) (RowIterator, error) {
+	roCtx := clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{"readonly": 1}))
+	return d.RunSQLStream(roCtx, query, params, limit)
+}
+
+// clickHouseRowIterator adapts driver.Rows to the RowIterator interface.
+type clickHouseRowIterator struct {
+	rows    driver.Rows
+	columns []string
+}
+
+func (it *clickHouseRowIterator) Columns() []string { return it.columns }
+
+func (it *clickHouseRowIterator) Next(ctx context.Context, batchSize int) ([][]interface{}, bool, error) {
+	batch := make([][]interface{}, 0, batchSize)
+	for len(batch) < batchSize && it.rows.Next() {
+		values := make([]interface{}, len(it.columns))
+		valuePointers := make([]interface{}, len(it.columns))
+		for i := range values {
+			valuePointers[i] = &values[i]
+		}
+		if err := it.rows.Scan(valuePointers...); err != nil {
+			return batch, false, fmt.Errorf("failed to scan row values: %w", err)
+		}
+		batch = append(batch, values)
+	}
+	if err := it.rows.Err(); err != nil {
+		return batch, false, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return batch, len(batch) == batchSize, nil
+}
+
+func (it *clickHouseRowIterator) Close() error {
+	return it.rows.Close()
+}
+
+// Estimate runs EXPLAIN ESTIMATE to get the rows/bytes/parts/marks
+// ClickHouse expects to scan, and EXPLAIN PLAN to flag a full-table scan
+// (no primary-key filter) before the query is actually executed.
+func (d *ClickHouseDriver) Estimate(ctx context.Context, query string) (*estimator.Estimate, error) {
+	if d.conn == nil {
+		return nil, fmt.Errorf("ClickHouse not connected")
+	}
+
+	estimateQuery := fmt.Sprintf("EXPLAIN ESTIMATE %s", query)
+	rows, err := d.conn.Query(ctx, estimateQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate query: %w", err)
+	}
+	defer rows.Close()
+
+	est := &estimator.Estimate{}
+	for rows.Next() {
+		var database, table string
+		var parts, rowsEstimate, marks uint64
+		if err := rows.Scan(&database, &table, &parts, &rowsEstimate, &marks); err != nil {
+			return nil, fmt.Errorf("failed to scan estimate row: %w", err)
+		}
+		est.EstimatedRows += rowsEstimate
+		// ClickHouse EXPLAIN ESTIMATE doesn't report bytes directly; marks
+		// are fixed-size index granules, so approximate bytes from them.
+		est.EstimatedBytes += marks * 8192
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating estimate rows: %w", err)
+	}
+
+	planQuery := fmt.Sprintf("EXPLAIN PLAN indexes=1 %s", query)
+	planRows, err := d.conn.Query(ctx, planQuery)
+	if err == nil {
+		defer planRows.Close()
+		var lines []string
+		for planRows.Next() {
+			var line string
+			if planRows.Scan(&line) == nil {
+				lines = append(lines, line)
+			}
+		}
+		// The "Indexes:" block for a ReadFromMergeTree node is never on the
+		// same line - it's nested on the lines immediately below it - so
+		// look ahead rather than matching the node's own line.
+		for i, line := range lines {
+			if !strings.Contains(line, "ReadFromMergeTree") {
+				continue
+			}
+			usedIndex := false
+			for _, sub := range lines[i+1:] {
+				if indentOf(sub) <= indentOf(line) {
+					break
+				}
+				if strings.Contains(sub, "Indexes:") {
+					usedIndex = true
+					break
+				}
+			}
+			if !usedIndex {
+				est.FullTableScan = true
+			}
+		}
+	}
+
+	return est, nil
+}
+
+// indentOf returns the number of leading whitespace characters in a line of
+// ClickHouse's indentation-nested EXPLAIN PLAN text output.
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// SchemaFingerprint hashes every table's metadata_modification_time, which
+// ClickHouse bumps on DDL (CREATE/ALTER/DROP, and MergeTree mutations),
+// so the fingerprint changes whenever a referenced table's definition or
+// data changes.
+func (d *ClickHouseDriver) SchemaFingerprint(ctx context.Context) (string, error) {
+	if d.conn == nil {
+		return "", fmt.Errorf("ClickHouse not connected")
+	}
+
+	query := `
+		SELECT lower(hex(SHA256(arrayStringConcat(
+			arraySort(groupArray(database || '.' || name || ':' || toString(metadata_modification_time))),
+			','
+		))))
+		FROM system.tables
+		WHERE database NOT IN ('system', 'information_schema', 'INFORMATION_SCHEMA')`
+
+	var fingerprint string
+	if err := d.conn.QueryRow(ctx, query).Scan(&fingerprint); err != nil {
+		return "", fmt.Errorf("failed to compute schema fingerprint: %w", err)
+	}
+	return fingerprint, nil
+}
+
 // GetType returns the database type
 func (d *ClickHouseDriver) GetType() DatabaseType {
 	return DatabaseTypeClickHouse
@@ -0,0 +1,23 @@
+package db
+
+import "database-mcp/internal/db/builder"
+
+// BuilderDialect maps a DatabaseType to the quoting/placeholder dialect
+// the builder package should render SQL in.
+func BuilderDialect(dbType DatabaseType) builder.Dialect {
+	switch dbType {
+	case DatabaseTypePostgreSQL:
+		return builder.DialectPostgreSQL
+	case DatabaseTypeClickHouse:
+		return builder.DialectClickHouse
+	case DatabaseTypeMySQL:
+		return builder.DialectMySQL
+	case DatabaseTypeSQLite:
+		// SQLite accepts standard double-quoted identifiers.
+		return builder.DialectPostgreSQL
+	case DatabaseTypeMSSQL:
+		return builder.DialectMSSQL
+	default:
+		return builder.DialectPostgreSQL
+	}
+}
@@ -2,6 +2,8 @@ package db
 
 import (
 	"context"
+
+	"database-mcp/internal/safety/estimator"
 	"database-mcp/internal/types"
 )
 
@@ -11,6 +13,9 @@ type DatabaseType string
 const (
 	DatabaseTypePostgreSQL DatabaseType = "postgresql"
 	DatabaseTypeClickHouse DatabaseType = "clickhouse"
+	DatabaseTypeMySQL      DatabaseType = "mysql"
+	DatabaseTypeSQLite     DatabaseType = "sqlite"
+	DatabaseTypeMSSQL      DatabaseType = "mssql"
 )
 
 // DatabaseDriver interface defines the contract for database drivers
@@ -26,10 +31,82 @@ type DatabaseDriver interface {
 	DescribeTable(ctx context.Context, schema, table string) (*types.TableDescription, error)
 
 	// Query operations
-	RunSQL(ctx context.Context, query string, limit int) (*types.QueryResult, error)
+	RunSQL(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error)
+
+	// RunSQLReadOnly behaves like RunSQL, but executes query inside an
+	// explicit read-only transaction or session setting (Postgres:
+	// BEGIN READ ONLY/ROLLBACK; MySQL: START TRANSACTION READ ONLY;
+	// ClickHouse: the readonly=1 query setting), so a write that slips
+	// past query classification is still refused by the database itself.
+	RunSQLReadOnly(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error)
+
 	ExplainQuery(ctx context.Context, query string) (*types.ExplainResult, error)
 
+	// Estimate returns the driver's cost/cardinality estimate for query,
+	// used by the run_sql guardrail to refuse or warn on expensive queries
+	// before they execute.
+	Estimate(ctx context.Context, query string) (*estimator.Estimate, error)
+
+	// RunSQLStream executes query like RunSQL but returns a RowIterator
+	// instead of buffering every row, so a caller can page through a large
+	// result set in bounded-size batches.
+	RunSQLStream(ctx context.Context, query string, params []interface{}, limit int) (RowIterator, error)
+
+	// RunSQLStreamReadOnly behaves like RunSQLStream, but executes query
+	// through the same read-only enforcement as RunSQLReadOnly, so a
+	// streamed query gets the same database-level write refusal a
+	// buffered one does.
+	RunSQLStreamReadOnly(ctx context.Context, query string, params []interface{}, limit int) (RowIterator, error)
+
+	// BeginReadOnlySnapshot opens a point-in-time, read-only ReadSession
+	// backed by a single connection, so a caller can bundle several schema
+	// and data reads into one consistent view instead of each hitting its
+	// own connection and possibly its own snapshot. The session must be
+	// closed (which rolls back its underlying transaction) when the caller
+	// is done with it.
+	BeginReadOnlySnapshot(ctx context.Context) (ReadSession, error)
+
+	// WithAdvisoryLock takes a dialect-appropriate, session-scoped advisory
+	// lock identified by key, runs fn, then releases the lock - all on one
+	// pinned connection, so the release always targets the same session
+	// that took the lock (acquiring and releasing on different pooled
+	// connections is a no-op on the release and leaves the lock held until
+	// that connection is closed). Postgres: pg_advisory_lock/unlock;
+	// MySQL/MariaDB: GET_LOCK/RELEASE_LOCK; SQL Server: sp_getapplock/
+	// sp_releaseapplock. SQLite's connection pool is already capped at one
+	// connection (see SQLiteDriver.Connect) and ClickHouse has no
+	// cross-session locking primitive, so both just run fn directly.
+	WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) error
+
+	// SchemaFingerprint returns a short string that changes whenever DDL or
+	// heavy DML touches a table this driver can see, derived from each
+	// database's own change-tracking metadata (e.g. Postgres's
+	// pg_stat_user_tables/pg_class.xmin, ClickHouse's
+	// system.tables.metadata_modification_time). Manager uses it as part of
+	// the result-cache key so a cached query/explain/schema result
+	// auto-invalidates the moment the schema or data it reflects changes,
+	// without the cache needing to parse table dependencies out of SQL.
+	SchemaFingerprint(ctx context.Context) (string, error)
+
 	// Metadata
 	GetType() DatabaseType
 	GetVersion(ctx context.Context) (string, error)
 }
+
+// ReadSession is a single point-in-time, read-only view opened by
+// BeginReadOnlySnapshot. Every call made through it runs against the same
+// underlying connection/transaction, so schema and data reads it bundles
+// stay consistent with each other even as concurrent writers commit
+// elsewhere.
+type ReadSession interface {
+	ListSchemas(ctx context.Context) ([]types.Schema, error)
+	ListTables(ctx context.Context, schema string) ([]types.Table, error)
+	DescribeTable(ctx context.Context, schema, table string) (*types.TableDescription, error)
+	RunSQL(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error)
+	ExplainQuery(ctx context.Context, query string) (*types.ExplainResult, error)
+
+	// Close rolls back the session's transaction (if any) and releases its
+	// connection. Safe to call once; callers should defer it immediately
+	// after BeginReadOnlySnapshot succeeds.
+	Close(ctx context.Context) error
+}
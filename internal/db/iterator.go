@@ -0,0 +1,19 @@
+package db
+
+import "context"
+
+// RowIterator yields a query's result rows in bounded-size batches so a
+// caller can page through a large result set instead of buffering it all
+// in memory, as RunSQL does.
+type RowIterator interface {
+	// Columns returns the result's column names.
+	Columns() []string
+
+	// Next returns up to batchSize rows. more is false once the iterator
+	// is exhausted (rows may still be non-empty on the final call).
+	Next(ctx context.Context, batchSize int) (rows [][]interface{}, more bool, err error)
+
+	// Close releases the underlying driver resources. Safe to call
+	// multiple times.
+	Close() error
+}
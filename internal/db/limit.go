@@ -0,0 +1,66 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// injectLimit appends "LIMIT n" to query if limit is positive and query
+// has no top-level LIMIT clause of its own, for drivers whose dialect
+// doesn't otherwise bound the row count it returns.
+func injectLimit(query string, limit int) string {
+	if limit <= 0 || hasTopLevelKeyword(query, "LIMIT") {
+		return query
+	}
+	return fmt.Sprintf("%s LIMIT %d", strings.TrimRight(query, ";"), limit)
+}
+
+// topLevelKeywordIndex returns the index of the first occurrence of
+// keyword in query that lies outside any parenthesized subquery/CTE body
+// and outside string literals, or -1 if none is found. A plain substring
+// search over the whole query text wrongly matches a keyword that only
+// applies to an inner subquery (e.g. "SELECT * FROM (SELECT * FROM t
+// LIMIT 10) sub"), leaving the outer query itself unbounded.
+func topLevelKeywordIndex(query, keyword string) int {
+	upper := strings.ToUpper(query)
+	keyword = strings.ToUpper(keyword)
+	depth := 0
+	inSingle, inDouble := false, false
+
+	for i := 0; i < len(upper); i++ {
+		c := upper[i]
+		switch {
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+		case inSingle || inDouble:
+			// inside a string literal; nothing else to check
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && strings.HasPrefix(upper[i:], keyword) &&
+			(i == 0 || isWordBoundary(upper[i-1])) &&
+			(i+len(keyword) == len(upper) || isWordBoundary(upper[i+len(keyword)])):
+			return i
+		}
+	}
+	return -1
+}
+
+// hasTopLevelKeyword reports whether query has keyword at its top level,
+// outside any parenthesized subquery/CTE body and outside string literals.
+func hasTopLevelKeyword(query, keyword string) bool {
+	return topLevelKeywordIndex(query, keyword) >= 0
+}
+
+// isWordBoundary reports whether c can't be part of a SQL identifier or
+// keyword, used to make sure a keyword match isn't actually the tail or
+// head of a longer identifier (e.g. a column named "ROWLIMIT").
+func isWordBoundary(c byte) bool {
+	isWordChar := c == '_' || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+	return !isWordChar
+}
@@ -2,62 +2,75 @@ package db
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/url"
 	"strings"
+	"time"
 
+	"database-mcp/internal/db/builder"
+	"database-mcp/internal/db/cache"
+	"database-mcp/internal/safety/classifier"
+	"database-mcp/internal/safety/estimator"
 	"database-mcp/internal/types"
 )
 
-// Manager manages multiple database connections
+// Manager manages connections to an arbitrary set of named databases, of
+// potentially different types, so an agent can join-explore a polyglot
+// environment through one MCP server. "primary" must always be configured;
+// any other names (e.g. "analytics") are optional.
 type Manager struct {
-	primaryDriver   DatabaseDriver
-	analyticsDriver DatabaseDriver
-	primaryURL      string
-	analyticsURL    string
+	urls    map[string]string
+	drivers map[string]DatabaseDriver
+
+	cache    cache.Cache
+	cacheTTL time.Duration
 }
 
-// NewManager creates a new database manager
-func NewManager(primaryURL, analyticsURL string) (*Manager, error) {
-	if primaryURL == "" {
+// NewManager creates a new database manager for the given name -> DSN map.
+// A "primary" entry is required.
+func NewManager(urls map[string]string) (*Manager, error) {
+	if urls["primary"] == "" {
 		return nil, fmt.Errorf("primary database URL is required")
 	}
 
 	return &Manager{
-		primaryURL:   primaryURL,
-		analyticsURL: analyticsURL,
+		urls:    urls,
+		drivers: make(map[string]DatabaseDriver, len(urls)),
 	}, nil
 }
 
-// Connect establishes connections to all configured databases
+// Connect establishes connections to all configured databases. A failure
+// to connect "primary" is fatal; other databases are skipped with a
+// warning so one misconfigured secondary doesn't take down the server.
 func (m *Manager) Connect(ctx context.Context) error {
-	// Connect to primary database
-	primaryDriver, err := createDriver(m.primaryURL)
+	primaryDriver, err := createDriver(m.urls["primary"])
 	if err != nil {
 		return fmt.Errorf("failed to create primary driver: %w", err)
 	}
-
-	if err := primaryDriver.Connect(ctx, m.primaryURL); err != nil {
+	if err := primaryDriver.Connect(ctx, m.urls["primary"]); err != nil {
 		return fmt.Errorf("failed to connect to primary database: %w", err)
 	}
-
-	m.primaryDriver = primaryDriver
+	m.drivers["primary"] = primaryDriver
 	slog.Info("Connected to primary database", "type", primaryDriver.GetType())
 
-	// Connect to analytics database if configured
-	if m.analyticsURL != "" {
-		analyticsDriver, err := createDriver(m.analyticsURL)
+	for name, dsn := range m.urls {
+		if name == "primary" || dsn == "" {
+			continue
+		}
+
+		driver, err := createDriver(dsn)
 		if err != nil {
-			slog.Warn("Failed to create analytics driver", "error", err)
-		} else {
-			if err := analyticsDriver.Connect(ctx, m.analyticsURL); err != nil {
-				slog.Warn("Failed to connect to analytics database", "error", err)
-			} else {
-				m.analyticsDriver = analyticsDriver
-				slog.Info("Connected to analytics database", "type", analyticsDriver.GetType())
-			}
+			slog.Warn("Failed to create driver", "database", name, "error", err)
+			continue
 		}
+		if err := driver.Connect(ctx, dsn); err != nil {
+			slog.Warn("Failed to connect to database", "database", name, "error", err)
+			continue
+		}
+		m.drivers[name] = driver
+		slog.Info("Connected to database", "database", name, "type", driver.GetType())
 	}
 
 	return nil
@@ -67,15 +80,9 @@ func (m *Manager) Connect(ctx context.Context) error {
 func (m *Manager) Close() error {
 	var errs []error
 
-	if m.primaryDriver != nil {
-		if err := m.primaryDriver.Close(); err != nil {
-			errs = append(errs, fmt.Errorf("primary database close error: %w", err))
-		}
-	}
-
-	if m.analyticsDriver != nil {
-		if err := m.analyticsDriver.Close(); err != nil {
-			errs = append(errs, fmt.Errorf("analytics database close error: %w", err))
+	for name, driver := range m.drivers {
+		if err := driver.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s database close error: %w", name, err))
 		}
 	}
 
@@ -86,22 +93,135 @@ func (m *Manager) Close() error {
 	return nil
 }
 
-// GetDriver returns the appropriate driver based on database name
+// GetDriver returns the driver for the named database ("" defaults to
+// "primary").
 func (m *Manager) GetDriver(database string) (DatabaseDriver, error) {
-	switch database {
-	case "primary", "":
-		if m.primaryDriver == nil {
-			return nil, fmt.Errorf("primary database not connected")
-		}
-		return m.primaryDriver, nil
-	case "analytics":
-		if m.analyticsDriver == nil {
-			return nil, fmt.Errorf("analytics database not connected or configured")
+	if database == "" {
+		database = "primary"
+	}
+
+	driver, ok := m.drivers[database]
+	if !ok {
+		if _, configured := m.urls[database]; configured {
+			return nil, fmt.Errorf("%s database not connected", database)
 		}
-		return m.analyticsDriver, nil
-	default:
 		return nil, fmt.Errorf("unknown database: %s", database)
 	}
+	return driver, nil
+}
+
+// DatabaseNames returns the names of all connected databases, "primary"
+// first, for building MCP tool schemas with a live `database` enum.
+func (m *Manager) DatabaseNames() []string {
+	names := make([]string, 0, len(m.drivers))
+	if _, ok := m.drivers["primary"]; ok {
+		names = append(names, "primary")
+	}
+	for name := range m.drivers {
+		if name != "primary" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// SetCache installs the result cache consulted by RunSQL, RunSQLReadOnly,
+// ExplainQuery, and ListSchemas, and the default TTL applied to entries
+// they write. Passing a nil cache (the zero value of Manager.cache)
+// disables caching entirely.
+func (m *Manager) SetCache(c cache.Cache, ttl time.Duration) {
+	m.cache = c
+	m.cacheTTL = ttl
+}
+
+// CacheStats returns the result cache's lifetime hit/miss/eviction
+// counters and size, or the zero Stats if no cache is configured.
+func (m *Manager) CacheStats() cache.Stats {
+	if m.cache == nil {
+		return cache.Stats{}
+	}
+	return m.cache.Stats()
+}
+
+// InvalidateCache clears cached entries for database, or just the ones
+// tagged with table (as seen in a cached query's FROM/JOIN clauses) when
+// table is non-empty. It's a no-op returning 0 when no cache is
+// configured.
+func (m *Manager) InvalidateCache(database, table string) int {
+	if m.cache == nil {
+		return 0
+	}
+	return m.cache.Invalidate(cache.TagPrefix(database, table))
+}
+
+// isCacheableRead reports whether query is safe to serve from or populate
+// the result cache - only statements the classifier is confident are pure
+// reads, so a write that slips past classification is never short-circuited
+// by a stale cache hit instead of actually executing.
+func isCacheableRead(query string) bool {
+	classification, err := classifier.Classify(query)
+	return err == nil && classification.Category == classifier.CategoryRead
+}
+
+// cacheGet consults the result cache for (kind, database, query, args)
+// against driver's current schema fingerprint, decoding a hit into dest (a
+// pointer). It returns the key a subsequent cacheSet should use to store a
+// fresh result; an empty key means caching is disabled, skipped via
+// CacheOverride, or the fingerprint couldn't be computed, and the caller
+// should not call cacheSet at all.
+func (m *Manager) cacheGet(ctx context.Context, kind, database string, driver DatabaseDriver, query string, args []interface{}, dest interface{}) (key string, hit bool) {
+	if m.cache == nil {
+		return "", false
+	}
+	if override, ok := CacheOverrideFromContext(ctx); ok && override.Skip {
+		return "", false
+	}
+
+	fingerprint, err := driver.SchemaFingerprint(ctx)
+	if err != nil {
+		slog.Warn("Failed to compute schema fingerprint, skipping cache", "database", database, "error", err)
+		return "", false
+	}
+
+	key = cache.Key(kind, database, query, args, fingerprint)
+	raw, found := m.cache.Get(key)
+	if !found {
+		return key, false
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		return key, false
+	}
+	return key, true
+}
+
+// cacheSet stores value under key (as returned by cacheGet), honoring any
+// per-call CacheOverride on ctx. A zero-value key is a no-op - it means the
+// matching cacheGet already determined this call shouldn't populate the
+// cache. The entry is tagged with database and every table query's
+// FROM/JOIN clauses mention, so cache_invalidate can clear it by either.
+func (m *Manager) cacheSet(ctx context.Context, key, database, query string, value interface{}) {
+	if key == "" {
+		return
+	}
+	ttl := m.cacheTTL
+	if override, ok := CacheOverrideFromContext(ctx); ok {
+		if override.Skip {
+			return
+		}
+		if override.TTL > 0 {
+			ttl = override.TTL
+		}
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	tags := []string{cache.TagPrefix(database, "")}
+	for _, table := range referencedTables(query) {
+		tags = append(tags, cache.TagPrefix(database, table))
+	}
+	m.cache.Set(key, raw, ttl, tags)
 }
 
 // ListSchemas lists schemas from the specified database
@@ -110,7 +230,18 @@ func (m *Manager) ListSchemas(ctx context.Context, database string) ([]types.Sch
 	if err != nil {
 		return nil, err
 	}
-	return driver.ListSchemas(ctx)
+
+	var cached []types.Schema
+	key, hit := m.cacheGet(ctx, "list_schemas", database, driver, "", nil, &cached)
+	if hit {
+		return cached, nil
+	}
+
+	schemas, err := driver.ListSchemas(ctx)
+	if err == nil {
+		m.cacheSet(ctx, key, database, "", schemas)
+	}
+	return schemas, err
 }
 
 // ListTables lists tables from the specified database and schema
@@ -131,13 +262,143 @@ func (m *Manager) DescribeTable(ctx context.Context, database, schema, table str
 	return driver.DescribeTable(ctx, schema, table)
 }
 
-// RunSQL executes a SQL query on the specified database
-func (m *Manager) RunSQL(ctx context.Context, database, query string, limit int) (*types.QueryResult, error) {
+// DescribeSpatial reports PostGIS metadata (SRID, geometry subtype, GiST
+// index coverage) for a table's geometry/geography columns, so a caller can
+// generate a correct spatial predicate without round-tripping through
+// EXPLAIN first. Only supported for PostgreSQL/PostGIS databases.
+func (m *Manager) DescribeSpatial(ctx context.Context, database, schema, table string) ([]types.SpatialColumn, error) {
+	driver, err := m.GetDriver(database)
+	if err != nil {
+		return nil, err
+	}
+
+	pgDriver, ok := driver.(*PostgreSQLDriver)
+	if !ok {
+		return nil, fmt.Errorf("describe_spatial is only supported for PostgreSQL/PostGIS databases, got %s", driver.GetType())
+	}
+	return pgDriver.DescribeSpatial(ctx, schema, table)
+}
+
+// RunSQL executes a SQL query on the specified database. params may be a
+// positional []interface{} matched against `?` placeholders, a
+// map[string]interface{} of named binds matched against `:name`
+// placeholders, or nil for an unparameterized query. The query is rebound
+// to the driver's native placeholder style before it reaches the driver.
+func (m *Manager) RunSQL(ctx context.Context, database, query string, params interface{}, limit int) (*types.QueryResult, error) {
 	driver, err := m.GetDriver(database)
 	if err != nil {
 		return nil, err
 	}
-	return driver.RunSQL(ctx, query, limit)
+
+	boundQuery, args, err := BindParams(driver.GetType(), query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isCacheableRead(boundQuery) {
+		return driver.RunSQL(ctx, boundQuery, args, limit)
+	}
+
+	var cached types.QueryResult
+	key, hit := m.cacheGet(ctx, "run_sql", database, driver, boundQuery, args, &cached)
+	if hit {
+		return &cached, nil
+	}
+
+	result, err := driver.RunSQL(ctx, boundQuery, args, limit)
+	if err == nil {
+		m.cacheSet(ctx, key, database, boundQuery, result)
+	}
+	return result, err
+}
+
+// RunBuilt renders sb to SQL for the specified database's dialect and runs
+// it through RunSQL, returning the rendered SQL alongside the result so a
+// caller (typically an LLM composing a query structurally) can surface
+// exactly what ran without a second ToSQL call or any string concatenation
+// of its own.
+func (m *Manager) RunBuilt(ctx context.Context, database string, sb *builder.SelectBuilder, limit int) (string, *types.QueryResult, error) {
+	driver, err := m.GetDriver(database)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql, args, err := sb.ToSQL(BuilderDialect(driver.GetType()))
+	if err != nil {
+		return "", nil, err
+	}
+
+	result, err := m.RunSQL(ctx, database, sql, args, limit)
+	return sql, result, err
+}
+
+// RunSQLReadOnly behaves like RunSQL, but asks the driver to enforce
+// read-only execution at the database level, for use when the server is
+// configured to refuse writes even if query classification misses one.
+func (m *Manager) RunSQLReadOnly(ctx context.Context, database, query string, params interface{}, limit int) (*types.QueryResult, error) {
+	driver, err := m.GetDriver(database)
+	if err != nil {
+		return nil, err
+	}
+
+	boundQuery, args, err := BindParams(driver.GetType(), query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isCacheableRead(boundQuery) {
+		return driver.RunSQLReadOnly(ctx, boundQuery, args, limit)
+	}
+
+	// Keyed identically to RunSQL's cache entries ("run_sql") - the result
+	// of a read-shaped query doesn't depend on whether it ran through a
+	// read-only transaction, so the two code paths can share cache hits.
+	var cached types.QueryResult
+	key, hit := m.cacheGet(ctx, "run_sql", database, driver, boundQuery, args, &cached)
+	if hit {
+		return &cached, nil
+	}
+
+	result, err := driver.RunSQLReadOnly(ctx, boundQuery, args, limit)
+	if err == nil {
+		m.cacheSet(ctx, key, database, boundQuery, result)
+	}
+	return result, err
+}
+
+// WithReadSnapshot opens a read-only snapshot session on database and
+// passes it to fn, so a bundle of schema and data reads backing a single
+// MCP tool invocation (e.g. "summarize this schema and sample 5 tables")
+// sees one consistent point-in-time view instead of each call racing
+// concurrent writers on its own connection. The session is always rolled
+// back when this returns, regardless of fn's error.
+func (m *Manager) WithReadSnapshot(ctx context.Context, database string, fn func(ReadSession) error) error {
+	driver, err := m.GetDriver(database)
+	if err != nil {
+		return err
+	}
+
+	session, err := driver.BeginReadOnlySnapshot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open read snapshot on %s: %w", database, err)
+	}
+	defer session.Close(ctx)
+
+	return fn(session)
+}
+
+// WithAdvisoryLock takes a dialect-appropriate advisory lock identified by
+// key on database and runs fn while holding it, so callers like the
+// migration Runner can serialize a multi-statement critical section
+// across concurrent callers without it being undone by a pooled
+// connection switch between acquiring and releasing the lock.
+func (m *Manager) WithAdvisoryLock(ctx context.Context, database string, key int64, fn func(ctx context.Context) error) error {
+	driver, err := m.GetDriver(database)
+	if err != nil {
+		return err
+	}
+
+	return driver.WithAdvisoryLock(ctx, key, fn)
 }
 
 // ExplainQuery explains a SQL query on the specified database
@@ -146,28 +407,77 @@ func (m *Manager) ExplainQuery(ctx context.Context, database, query string) (*ty
 	if err != nil {
 		return nil, err
 	}
-	return driver.ExplainQuery(ctx, query)
+
+	var cached types.ExplainResult
+	key, hit := m.cacheGet(ctx, "explain_sql", database, driver, query, nil, &cached)
+	if hit {
+		return &cached, nil
+	}
+
+	result, err := driver.ExplainQuery(ctx, query)
+	if err == nil {
+		m.cacheSet(ctx, key, database, query, result)
+	}
+	return result, err
+}
+
+// Estimate returns the driver's cost/cardinality estimate for query on the
+// specified database, used by the run_sql guardrail.
+func (m *Manager) Estimate(ctx context.Context, database, query string) (*estimator.Estimate, error) {
+	driver, err := m.GetDriver(database)
+	if err != nil {
+		return nil, err
+	}
+	return driver.Estimate(ctx, query)
+}
+
+// RunSQLStream executes a SQL query on the specified database and returns a
+// RowIterator for paging through the result instead of buffering it.
+func (m *Manager) RunSQLStream(ctx context.Context, database, query string, params interface{}, limit int) (RowIterator, error) {
+	driver, err := m.GetDriver(database)
+	if err != nil {
+		return nil, err
+	}
+
+	boundQuery, args, err := BindParams(driver.GetType(), query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.RunSQLStream(ctx, boundQuery, args, limit)
+}
+
+// RunSQLStreamReadOnly behaves like RunSQLStream, but asks the driver to
+// enforce read-only execution at the database level, for use when the
+// server is configured to refuse writes even if query classification
+// misses one.
+func (m *Manager) RunSQLStreamReadOnly(ctx context.Context, database, query string, params interface{}, limit int) (RowIterator, error) {
+	driver, err := m.GetDriver(database)
+	if err != nil {
+		return nil, err
+	}
+
+	boundQuery, args, err := BindParams(driver.GetType(), query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.RunSQLStreamReadOnly(ctx, boundQuery, args, limit)
 }
 
 // GetDatabaseInfo returns information about connected databases
 func (m *Manager) GetDatabaseInfo(ctx context.Context) map[string]interface{} {
 	info := make(map[string]interface{})
 
-	if m.primaryDriver != nil {
-		version, _ := m.primaryDriver.GetVersion(ctx)
-		info["primary"] = map[string]interface{}{
-			"type":    m.primaryDriver.GetType(),
+	for name, driver := range m.drivers {
+		version, _ := driver.GetVersion(ctx)
+		info[name] = map[string]interface{}{
+			"type":    driver.GetType(),
 			"version": version,
 		}
 	}
 
-	if m.analyticsDriver != nil {
-		version, _ := m.analyticsDriver.GetVersion(ctx)
-		info["analytics"] = map[string]interface{}{
-			"type":    m.analyticsDriver.GetType(),
-			"version": version,
-		}
-	}
+	info["cache"] = m.CacheStats()
 
 	return info
 }
@@ -185,6 +495,12 @@ func createDriver(dsn string) (DatabaseDriver, error) {
 		return NewPostgreSQLDriver(), nil
 	case "clickhouse":
 		return NewClickHouseDriver(), nil
+	case "mysql":
+		return NewMySQLDriver(), nil
+	case "sqlite", "file":
+		return NewSQLiteDriver(), nil
+	case "sqlserver", "mssql":
+		return NewMSSQLDriver(), nil
 	default:
 		return nil, fmt.Errorf("unsupported database scheme: %s", scheme)
 	}
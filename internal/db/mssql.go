@@ -0,0 +1,619 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"database-mcp/internal/safety/estimator"
+	"database-mcp/internal/types"
+
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// MSSQLDriver implements DatabaseDriver for Microsoft SQL Server
+type MSSQLDriver struct {
+	db  *sql.DB
+	dsn string
+}
+
+// NewMSSQLDriver creates a new MSSQL driver
+func NewMSSQLDriver() DatabaseDriver {
+	return &MSSQLDriver{}
+}
+
+// Connect establishes a connection to SQL Server
+func (d *MSSQLDriver) Connect(ctx context.Context, dsn string) error {
+	d.dsn = dsn
+
+	conn, err := sql.Open("sqlserver", dsn)
+	if err != nil {
+		return fmt.Errorf("failed to create MSSQL connection: %w", err)
+	}
+
+	conn.SetMaxOpenConns(10)
+	conn.SetMaxIdleConns(5)
+	conn.SetConnMaxLifetime(time.Hour)
+
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to ping MSSQL database: %w", err)
+	}
+
+	d.db = conn
+	slog.Info("Connected to MSSQL", "dsn", maskDSN(dsn))
+	return nil
+}
+
+// Close closes the MSSQL connection
+func (d *MSSQLDriver) Close() error {
+	if d.db != nil {
+		err := d.db.Close()
+		d.db = nil
+		slog.Info("Closed MSSQL connection")
+		return err
+	}
+	return nil
+}
+
+// Ping checks if the MSSQL connection is alive
+func (d *MSSQLDriver) Ping(ctx context.Context) error {
+	if d.db == nil {
+		return fmt.Errorf("MSSQL not connected")
+	}
+	return d.db.PingContext(ctx)
+}
+
+// ListSchemas lists all MSSQL schemas
+func (d *MSSQLDriver) ListSchemas(ctx context.Context) ([]types.Schema, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MSSQL not connected")
+	}
+	return listMSSQLSchemas(ctx, d.db)
+}
+
+func listMSSQLSchemas(ctx context.Context, q sqlQuerier) ([]types.Schema, error) {
+	query := `
+		SELECT schema_name
+		FROM information_schema.schemata
+		WHERE schema_name NOT IN ('sys', 'INFORMATION_SCHEMA', 'guest', 'db_owner',
+			'db_accessadmin', 'db_securityadmin', 'db_ddladmin', 'db_backupoperator',
+			'db_datareader', 'db_datawriter', 'db_denydatareader', 'db_denydatawriter')
+		ORDER BY schema_name`
+
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schemas: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []types.Schema
+	for rows.Next() {
+		var schema types.Schema
+		if err := rows.Scan(&schema.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan schema row: %w", err)
+		}
+		schemas = append(schemas, schema)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schema rows: %w", err)
+	}
+
+	return schemas, nil
+}
+
+// ListTables lists tables in an MSSQL schema
+func (d *MSSQLDriver) ListTables(ctx context.Context, schema string) ([]types.Table, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MSSQL not connected")
+	}
+	return listMSSQLTables(ctx, d.db, schema)
+}
+
+func listMSSQLTables(ctx context.Context, q sqlQuerier, schema string) ([]types.Table, error) {
+	query := `
+		SELECT
+			t.table_name,
+			t.table_schema,
+			CASE WHEN t.table_type = 'VIEW' THEN 'view' ELSE 'table' END as table_type,
+			COALESCE(ep.value, ''),
+			p.rows
+		FROM information_schema.tables t
+		LEFT JOIN sys.tables st ON st.name = t.table_name AND SCHEMA_NAME(st.schema_id) = t.table_schema
+		LEFT JOIN sys.partitions p ON p.object_id = st.object_id AND p.index_id IN (0, 1)
+		LEFT JOIN sys.extended_properties ep ON ep.major_id = st.object_id AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+		WHERE t.table_schema = @p1
+		ORDER BY t.table_name`
+
+	rows, err := q.QueryContext(ctx, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []types.Table
+	for rows.Next() {
+		var table types.Table
+		var rowCount sql.NullInt64
+		if err := rows.Scan(&table.Name, &table.Schema, &table.Type, &table.Description, &rowCount); err != nil {
+			return nil, fmt.Errorf("failed to scan table row: %w", err)
+		}
+		if rowCount.Valid {
+			table.RowCount = &rowCount.Int64
+		}
+		tables = append(tables, table)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table rows: %w", err)
+	}
+
+	return tables, nil
+}
+
+// DescribeTable describes an MSSQL table
+func (d *MSSQLDriver) DescribeTable(ctx context.Context, schema, table string) (*types.TableDescription, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MSSQL not connected")
+	}
+	return describeMSSQLTable(ctx, d.db, schema, table)
+}
+
+func describeMSSQLTable(ctx context.Context, q sqlQuerier, schema, table string) (*types.TableDescription, error) {
+	tableQuery := `
+		SELECT
+			t.table_schema,
+			t.table_name,
+			CASE WHEN t.table_type = 'VIEW' THEN 'view' ELSE 'table' END as table_type,
+			COALESCE(ep.value, ''),
+			p.rows
+		FROM information_schema.tables t
+		LEFT JOIN sys.tables st ON st.name = t.table_name AND SCHEMA_NAME(st.schema_id) = t.table_schema
+		LEFT JOIN sys.partitions p ON p.object_id = st.object_id AND p.index_id IN (0, 1)
+		LEFT JOIN sys.extended_properties ep ON ep.major_id = st.object_id AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+		WHERE t.table_schema = @p1 AND t.table_name = @p2`
+
+	var desc types.TableDescription
+	var rowCount sql.NullInt64
+	err := q.QueryRowContext(ctx, tableQuery, schema, table).Scan(
+		&desc.Schema, &desc.Name, &desc.Type, &desc.Description, &rowCount,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table info: %w", err)
+	}
+	if rowCount.Valid {
+		desc.RowCount = &rowCount.Int64
+	}
+
+	columnQuery := `
+		SELECT
+			c.column_name,
+			c.data_type +
+				CASE WHEN c.character_maximum_length IS NOT NULL
+					THEN '(' + CAST(c.character_maximum_length AS varchar) + ')'
+					ELSE '' END,
+			CASE WHEN c.is_nullable = 'YES' THEN 1 ELSE 0 END,
+			COALESCE(CAST(c.column_default AS varchar(4000)), ''),
+			CASE WHEN pk.column_name IS NOT NULL THEN 1 ELSE 0 END,
+			CASE WHEN fk.column_name IS NOT NULL THEN 1 ELSE 0 END,
+			CASE WHEN ix.column_name IS NOT NULL THEN 1 ELSE 0 END
+		FROM information_schema.columns c
+		LEFT JOIN (
+			SELECT ku.table_schema, ku.table_name, ku.column_name
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage ku
+				ON ku.constraint_name = tc.constraint_name AND ku.table_schema = tc.table_schema
+			WHERE tc.constraint_type = 'PRIMARY KEY'
+		) pk ON pk.table_schema = c.table_schema AND pk.table_name = c.table_name AND pk.column_name = c.column_name
+		LEFT JOIN (
+			SELECT ku.table_schema, ku.table_name, ku.column_name
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage ku
+				ON ku.constraint_name = tc.constraint_name AND ku.table_schema = tc.table_schema
+			WHERE tc.constraint_type = 'FOREIGN KEY'
+		) fk ON fk.table_schema = c.table_schema AND fk.table_name = c.table_name AND fk.column_name = c.column_name
+		LEFT JOIN (
+			SELECT sch.name AS table_schema, tab.name AS table_name, col.name AS column_name
+			FROM sys.index_columns ic
+			JOIN sys.indexes i ON i.object_id = ic.object_id AND i.index_id = ic.index_id
+			JOIN sys.tables tab ON tab.object_id = ic.object_id
+			JOIN sys.schemas sch ON sch.schema_id = tab.schema_id
+			JOIN sys.columns col ON col.object_id = ic.object_id AND col.column_id = ic.column_id
+		) ix ON ix.table_schema = c.table_schema AND ix.table_name = c.table_name AND ix.column_name = c.column_name
+		WHERE c.table_schema = @p1 AND c.table_name = @p2
+		ORDER BY c.ordinal_position`
+
+	rows, err := q.QueryContext(ctx, columnQuery, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var col types.Column
+		if err := rows.Scan(
+			&col.Name, &col.Type, &col.Nullable, &col.DefaultValue,
+			&col.IsPrimaryKey, &col.IsForeignKey, &col.IsIndex,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan column row: %w", err)
+		}
+		desc.Columns = append(desc.Columns, col)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating column rows: %w", err)
+	}
+
+	indexQuery := `
+		SELECT i.name, STRING_AGG(col.name, ',') WITHIN GROUP (ORDER BY ic.key_ordinal), i.is_unique
+		FROM sys.indexes i
+		JOIN sys.tables tab ON tab.object_id = i.object_id
+		JOIN sys.schemas sch ON sch.schema_id = tab.schema_id
+		JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+		JOIN sys.columns col ON col.object_id = ic.object_id AND col.column_id = ic.column_id
+		WHERE sch.name = @p1 AND tab.name = @p2 AND i.is_primary_key = 0 AND i.name IS NOT NULL
+		GROUP BY i.name, i.is_unique`
+
+	indexRows, err := q.QueryContext(ctx, indexQuery, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer indexRows.Close()
+
+	for indexRows.Next() {
+		var idx types.Index
+		var columnList string
+		if err := indexRows.Scan(&idx.Name, &columnList, &idx.IsUnique); err != nil {
+			return nil, fmt.Errorf("failed to scan index row: %w", err)
+		}
+		idx.Columns = strings.Split(columnList, ",")
+		desc.Indexes = append(desc.Indexes, idx)
+	}
+
+	return &desc, nil
+}
+
+// mssqlTopPattern matches a `SELECT [DISTINCT|ALL]` clause, the point at
+// which a `TOP (n)` clause must be inserted - MSSQL has no LIMIT, so the
+// driver's LIMIT-injection path rewrites the query instead of appending a
+// clause.
+var mssqlTopPattern = regexp.MustCompile(`(?i)^(\s*SELECT\s+(?:DISTINCT\s+|ALL\s+)?)`)
+
+// injectMSSQLTop inserts `TOP (limit)` right after the outer query's
+// leading SELECT (and DISTINCT/ALL, if present), unless that outer query
+// already specifies its own TOP or isn't a SELECT at all (e.g. an EXEC of
+// a stored procedure). The outer SELECT is found via a top-level,
+// depth-tracking scan rather than assuming the query starts with it, so a
+// `WITH cte AS (...) SELECT ...` query gets TOP on its final SELECT
+// instead of silently returning every row, and a subquery's own TOP
+// doesn't suppress injection on the outer query (mirrors injectLimit's
+// handling of a subquery's own LIMIT).
+func injectMSSQLTop(query string, limit int) string {
+	if limit <= 0 || hasTopLevelKeyword(query, "TOP") {
+		return query
+	}
+	idx := topLevelKeywordIndex(query, "SELECT")
+	if idx < 0 {
+		return query
+	}
+	loc := mssqlTopPattern.FindStringSubmatchIndex(query[idx:])
+	if loc == nil {
+		return query
+	}
+	insertAt := idx + loc[1]
+	return query[:insertAt] + fmt.Sprintf("TOP (%d) ", limit) + query[insertAt:]
+}
+
+// RunSQL executes a SQL query on SQL Server, binding params to the query's
+// `@p1`, `@p2`, ... placeholders.
+func (d *MSSQLDriver) RunSQL(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MSSQL not connected")
+	}
+
+	startTime := time.Now()
+	query = injectMSSQLTop(query, limit)
+
+	return scanSQLRows(ctx, d.db, query, params, startTime)
+}
+
+// RunSQLReadOnly behaves like RunSQL, but runs query inside a transaction
+// opened with sql.TxOptions{ReadOnly: true}, which go-mssqldb issues as a
+// transaction under SNAPSHOT/READ COMMITTED isolation refusing writes, so
+// the server itself refuses any write the query classifier missed.
+func (d *MSSQLDriver) RunSQLReadOnly(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MSSQL not connected")
+	}
+
+	query = injectMSSQLTop(query, limit)
+
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	startTime := time.Now()
+
+	return scanSQLRows(ctx, tx, query, params, startTime)
+}
+
+// BeginReadOnlySnapshot opens a `sql.TxOptions{ReadOnly: true, Isolation:
+// sql.LevelSnapshot}` transaction (SQL Server's row-versioning based
+// snapshot isolation) so every call made through the returned session sees
+// one consistent point-in-time view. If ctx carries a deadline (the MCP
+// server sets one from QUERY_TIMEOUT_MS), it is applied as a
+// `LOCK_TIMEOUT` session setting.
+func (d *MSSQLDriver) BeginReadOnlySnapshot(ctx context.Context) (ReadSession, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MSSQL not connected")
+	}
+
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelSnapshot})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start read-only snapshot transaction: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if ms := time.Until(deadline).Milliseconds(); ms > 0 {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCK_TIMEOUT %d", ms)); err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("failed to set LOCK_TIMEOUT: %w", err)
+			}
+		}
+	}
+
+	return &mssqlReadSession{tx: tx}, nil
+}
+
+// mssqlReadSession implements ReadSession on top of a single open sql.Tx
+// holding a read-only snapshot-isolation transaction.
+type mssqlReadSession struct {
+	tx *sql.Tx
+}
+
+func (s *mssqlReadSession) ListSchemas(ctx context.Context) ([]types.Schema, error) {
+	return listMSSQLSchemas(ctx, s.tx)
+}
+
+func (s *mssqlReadSession) ListTables(ctx context.Context, schema string) ([]types.Table, error) {
+	return listMSSQLTables(ctx, s.tx, schema)
+}
+
+func (s *mssqlReadSession) DescribeTable(ctx context.Context, schema, table string) (*types.TableDescription, error) {
+	return describeMSSQLTable(ctx, s.tx, schema, table)
+}
+
+func (s *mssqlReadSession) RunSQL(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
+	query = injectMSSQLTop(query, limit)
+	return scanSQLRows(ctx, s.tx, query, params, time.Now())
+}
+
+func (s *mssqlReadSession) ExplainQuery(ctx context.Context, query string) (*types.ExplainResult, error) {
+	return explainMSSQL(ctx, s.tx, query)
+}
+
+// Close rolls back the snapshot transaction. Safe to call once.
+func (s *mssqlReadSession) Close(ctx context.Context) error {
+	return s.tx.Rollback()
+}
+
+// RunSQLStream executes query on SQL Server and returns a RowIterator that
+// scans rows on demand instead of buffering the whole result set.
+func (d *MSSQLDriver) RunSQLStream(ctx context.Context, query string, params []interface{}, limit int) (RowIterator, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MSSQL not connected")
+	}
+
+	query = injectMSSQLTop(query, limit)
+
+	rows, err := d.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	return &sqlRowIterator{rows: rows, columns: columns}, nil
+}
+
+// RunSQLStreamReadOnly behaves like RunSQLStream, but runs query inside a
+// transaction opened with sql.TxOptions{ReadOnly: true}, which go-mssqldb
+// issues as a transaction refusing writes, so the server itself refuses
+// any write the query classifier missed. The transaction is rolled back
+// when the returned iterator is closed.
+func (d *MSSQLDriver) RunSQLStreamReadOnly(ctx context.Context, query string, params []interface{}, limit int) (RowIterator, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MSSQL not connected")
+	}
+
+	query = injectMSSQLTop(query, limit)
+
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, params...)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	return &txRowIterator{sqlRowIterator: sqlRowIterator{rows: rows, columns: columns}, tx: tx}, nil
+}
+
+// WithAdvisoryLock takes a session-scoped sp_getapplock on a single
+// connection pulled out of the pool, runs fn, then releases it on that
+// same connection before returning it to the pool.
+func (d *MSSQLDriver) WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) error {
+	if d.db == nil {
+		return fmt.Errorf("MSSQL not connected")
+	}
+
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	lockName := fmt.Sprintf("migrate:%d", key)
+	var res int
+	if err := conn.QueryRowContext(ctx, "DECLARE @res INT; EXEC @res = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = 10000; SELECT @res", lockName).Scan(&res); err != nil {
+		return fmt.Errorf("failed to acquire applock: %w", err)
+	}
+	if res < 0 {
+		return fmt.Errorf("failed to acquire applock %s: sp_getapplock returned %d", lockName, res)
+	}
+	defer conn.ExecContext(context.Background(), "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'", lockName)
+
+	return fn(ctx)
+}
+
+// explainMSSQL turns on SHOWPLAN_XML for the duration of q's next
+// statement, which makes SQL Server return the (estimated, not executed)
+// query plan as a single XML column instead of running the query.
+func explainMSSQL(ctx context.Context, q interface {
+	sqlQuerier
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}, query string) (*types.ExplainResult, error) {
+	startTime := time.Now()
+
+	if _, err := q.ExecContext(ctx, "SET SHOWPLAN_XML ON"); err != nil {
+		return nil, fmt.Errorf("failed to enable SHOWPLAN_XML: %w", err)
+	}
+	defer q.ExecContext(context.Background(), "SET SHOWPLAN_XML OFF")
+
+	var planXML string
+	if err := q.QueryRowContext(ctx, query).Scan(&planXML); err != nil {
+		return nil, fmt.Errorf("failed to get query plan: %w", err)
+	}
+
+	return &types.ExplainResult{
+		Query: query,
+		Plan: map[string]interface{}{
+			"format": "mssql_showplan_xml",
+			"raw":    planXML,
+		},
+		ExecutionTimeMs: time.Since(startTime).Milliseconds(),
+	}, nil
+}
+
+// ExplainQuery explains a SQL query on SQL Server
+func (d *MSSQLDriver) ExplainQuery(ctx context.Context, query string) (*types.ExplainResult, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MSSQL not connected")
+	}
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+	return explainMSSQL(ctx, conn, query)
+}
+
+// mssqlEstimatedRowsPattern pulls the first EstimateRows attribute out of
+// a showplan XML document, used by Estimate as a cheap approximation
+// without pulling in a full XML parser for a single attribute.
+var mssqlEstimatedRowsPattern = regexp.MustCompile(`EstimateRows="([0-9.eE+-]+)"`)
+
+// Estimate reads the SHOWPLAN_XML estimated plan for query and pulls its
+// top-level row estimate (the first RelOp's EstimateRows). It flags a full
+// scan when a Table Scan or Clustered Index Scan operator appears anywhere
+// in the plan, not only at its root.
+func (d *MSSQLDriver) Estimate(ctx context.Context, query string) (*estimator.Estimate, error) {
+	plan, err := d.ExplainQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	planXML, _ := plan.Plan["raw"].(string)
+	est := &estimator.Estimate{}
+	if m := mssqlEstimatedRowsPattern.FindStringSubmatch(planXML); m != nil {
+		if rows, err := strconv.ParseFloat(m[1], 64); err == nil {
+			est.EstimatedRows = uint64(rows)
+		}
+	}
+	if strings.Contains(planXML, `PhysicalOp="Table Scan"`) || strings.Contains(planXML, `PhysicalOp="Clustered Index Scan"`) {
+		est.FullTableScan = true
+	}
+
+	return est, nil
+}
+
+// SchemaFingerprint hashes every table's modify_date from sys.tables,
+// which SQL Server bumps on DDL and on certain heavy DML operations (e.g.
+// TRUNCATE, partition switches, index rebuilds), alongside its row count
+// from sys.partitions, so the fingerprint changes whenever a referenced
+// table's definition or data changes.
+func (d *MSSQLDriver) SchemaFingerprint(ctx context.Context) (string, error) {
+	if d.db == nil {
+		return "", fmt.Errorf("MSSQL not connected")
+	}
+
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT sch.name, tab.name, tab.modify_date, SUM(p.rows)
+		FROM sys.tables tab
+		JOIN sys.schemas sch ON sch.schema_id = tab.schema_id
+		JOIN sys.partitions p ON p.object_id = tab.object_id AND p.index_id IN (0, 1)
+		GROUP BY sch.name, tab.name, tab.modify_date
+		ORDER BY sch.name, tab.name`)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute schema fingerprint: %w", err)
+	}
+	defer rows.Close()
+
+	h := sha256.New()
+	for rows.Next() {
+		var schema, table string
+		var modifyDate time.Time
+		var rowCount int64
+		if err := rows.Scan(&schema, &table, &modifyDate, &rowCount); err != nil {
+			return "", fmt.Errorf("failed to scan schema fingerprint row: %w", err)
+		}
+		fmt.Fprintf(h, "%s.%s:%s:%d;", schema, table, modifyDate.UTC().Format(time.RFC3339Nano), rowCount)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("error iterating schema fingerprint rows: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// GetType returns the database type
+func (d *MSSQLDriver) GetType() DatabaseType {
+	return DatabaseTypeMSSQL
+}
+
+// GetVersion returns the SQL Server version
+func (d *MSSQLDriver) GetVersion(ctx context.Context) (string, error) {
+	if d.db == nil {
+		return "MSSQL (not connected)", nil
+	}
+
+	var version string
+	if err := d.db.QueryRowContext(ctx, "SELECT @@VERSION").Scan(&version); err != nil {
+		return "MSSQL (version unknown)", nil
+	}
+	return version, nil
+}
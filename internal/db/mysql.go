@@ -0,0 +1,635 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"database-mcp/internal/safety/estimator"
+	"database-mcp/internal/types"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// MySQLDriver implements DatabaseDriver for MySQL/MariaDB
+type MySQLDriver struct {
+	db  *sql.DB
+	dsn string
+}
+
+// NewMySQLDriver creates a new MySQL driver
+func NewMySQLDriver() DatabaseDriver {
+	return &MySQLDriver{}
+}
+
+// Connect establishes a connection to MySQL
+func (d *MySQLDriver) Connect(ctx context.Context, dsn string) error {
+	d.dsn = dsn
+
+	// database/sql expects the DSN without the mysql:// scheme
+	dataSourceName := strings.TrimPrefix(dsn, "mysql://")
+
+	conn, err := sql.Open("mysql", dataSourceName)
+	if err != nil {
+		return fmt.Errorf("failed to create MySQL connection: %w", err)
+	}
+
+	conn.SetMaxOpenConns(10)
+	conn.SetMaxIdleConns(5)
+	conn.SetConnMaxLifetime(time.Hour)
+
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to ping MySQL database: %w", err)
+	}
+
+	d.db = conn
+	slog.Info("Connected to MySQL", "dsn", maskDSN(dsn))
+	return nil
+}
+
+// Close closes the MySQL connection
+func (d *MySQLDriver) Close() error {
+	if d.db != nil {
+		err := d.db.Close()
+		d.db = nil
+		slog.Info("Closed MySQL connection")
+		return err
+	}
+	return nil
+}
+
+// Ping checks if the MySQL connection is alive
+func (d *MySQLDriver) Ping(ctx context.Context) error {
+	if d.db == nil {
+		return fmt.Errorf("MySQL not connected")
+	}
+	return d.db.PingContext(ctx)
+}
+
+// ListSchemas lists all MySQL databases (schemas)
+func (d *MySQLDriver) ListSchemas(ctx context.Context) ([]types.Schema, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MySQL not connected")
+	}
+	return listMySQLSchemas(ctx, d.db)
+}
+
+func listMySQLSchemas(ctx context.Context, q sqlQuerier) ([]types.Schema, error) {
+	query := `
+		SELECT schema_name
+		FROM information_schema.schemata
+		WHERE schema_name NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys')
+		ORDER BY schema_name`
+
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schemas: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []types.Schema
+	for rows.Next() {
+		var schema types.Schema
+		if err := rows.Scan(&schema.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan schema row: %w", err)
+		}
+		schemas = append(schemas, schema)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schema rows: %w", err)
+	}
+
+	return schemas, nil
+}
+
+// ListTables lists tables in a MySQL schema
+func (d *MySQLDriver) ListTables(ctx context.Context, schema string) ([]types.Table, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MySQL not connected")
+	}
+	return listMySQLTables(ctx, d.db, schema)
+}
+
+func listMySQLTables(ctx context.Context, q sqlQuerier, schema string) ([]types.Table, error) {
+	query := `
+		SELECT
+			table_name,
+			table_schema,
+			CASE WHEN table_type = 'VIEW' THEN 'view' ELSE 'table' END as table_type,
+			COALESCE(table_comment, ''),
+			table_rows,
+			COALESCE(engine, ''),
+			COALESCE(table_collation, '')
+		FROM information_schema.tables
+		WHERE table_schema = ?
+		ORDER BY table_name`
+
+	rows, err := q.QueryContext(ctx, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []types.Table
+	for rows.Next() {
+		var table types.Table
+		var rowCount sql.NullInt64
+		if err := rows.Scan(&table.Name, &table.Schema, &table.Type, &table.Description, &rowCount, &table.Engine, &table.Collation); err != nil {
+			return nil, fmt.Errorf("failed to scan table row: %w", err)
+		}
+		if rowCount.Valid {
+			table.RowCount = &rowCount.Int64
+		}
+		tables = append(tables, table)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table rows: %w", err)
+	}
+
+	return tables, nil
+}
+
+// DescribeTable describes a MySQL table
+func (d *MySQLDriver) DescribeTable(ctx context.Context, schema, table string) (*types.TableDescription, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MySQL not connected")
+	}
+	return describeMySQLTable(ctx, d.db, schema, table)
+}
+
+func describeMySQLTable(ctx context.Context, q sqlQuerier, schema, table string) (*types.TableDescription, error) {
+	tableQuery := `
+		SELECT
+			table_schema,
+			table_name,
+			CASE WHEN table_type = 'VIEW' THEN 'view' ELSE 'table' END as table_type,
+			COALESCE(table_comment, ''),
+			table_rows
+		FROM information_schema.tables
+		WHERE table_schema = ? AND table_name = ?`
+
+	var desc types.TableDescription
+	var rowCount sql.NullInt64
+	err := q.QueryRowContext(ctx, tableQuery, schema, table).Scan(
+		&desc.Schema, &desc.Name, &desc.Type, &desc.Description, &rowCount,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table info: %w", err)
+	}
+	if rowCount.Valid {
+		desc.RowCount = &rowCount.Int64
+	}
+
+	columnQuery := `
+		SELECT
+			c.column_name,
+			c.column_type,
+			CASE WHEN c.is_nullable = 'YES' THEN true ELSE false END,
+			COALESCE(c.column_default, ''),
+			COALESCE(c.column_comment, ''),
+			CASE WHEN c.column_key = 'PRI' THEN true ELSE false END,
+			CASE WHEN c.column_key = 'MUL' THEN true ELSE false END,
+			CASE WHEN c.column_key != '' THEN true ELSE false END
+		FROM information_schema.columns c
+		WHERE c.table_schema = ? AND c.table_name = ?
+		ORDER BY c.ordinal_position`
+
+	rows, err := q.QueryContext(ctx, columnQuery, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var col types.Column
+		if err := rows.Scan(
+			&col.Name, &col.Type, &col.Nullable, &col.DefaultValue, &col.Description,
+			&col.IsPrimaryKey, &col.IsForeignKey, &col.IsIndex,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan column row: %w", err)
+		}
+		desc.Columns = append(desc.Columns, col)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating column rows: %w", err)
+	}
+
+	indexQuery := `
+		SELECT index_name, GROUP_CONCAT(column_name ORDER BY seq_in_index), non_unique
+		FROM information_schema.statistics
+		WHERE table_schema = ? AND table_name = ? AND index_name != 'PRIMARY'
+		GROUP BY index_name, non_unique`
+
+	indexRows, err := q.QueryContext(ctx, indexQuery, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer indexRows.Close()
+
+	for indexRows.Next() {
+		var idx types.Index
+		var columnList string
+		var nonUnique bool
+		if err := indexRows.Scan(&idx.Name, &columnList, &nonUnique); err != nil {
+			return nil, fmt.Errorf("failed to scan index row: %w", err)
+		}
+		idx.Columns = strings.Split(columnList, ",")
+		idx.IsUnique = !nonUnique
+		desc.Indexes = append(desc.Indexes, idx)
+	}
+
+	return &desc, nil
+}
+
+// RunSQL executes a SQL query on MySQL, binding params to the query's `?`
+// placeholders.
+func (d *MySQLDriver) RunSQL(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MySQL not connected")
+	}
+
+	startTime := time.Now()
+
+	query = injectLimit(query, limit)
+
+	return scanSQLRows(ctx, d.db, query, params, startTime)
+}
+
+// RunSQLReadOnly behaves like RunSQL, but runs query inside a transaction
+// opened with sql.TxOptions{ReadOnly: true}, which the MySQL driver issues
+// as `START TRANSACTION READ ONLY`, so the server itself refuses any write
+// the query classifier missed.
+func (d *MySQLDriver) RunSQLReadOnly(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MySQL not connected")
+	}
+
+	query = injectLimit(query, limit)
+
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	startTime := time.Now()
+
+	return scanSQLRows(ctx, tx, query, params, startTime)
+}
+
+// BeginReadOnlySnapshot opens a `sql.TxOptions{ReadOnly: true}` transaction,
+// which InnoDB's default REPEATABLE READ isolation gives a consistent
+// snapshot of the data for, and lets every call made through the returned
+// session see that same snapshot alongside a consistent catalog read. If
+// ctx carries a deadline (the MCP server sets one from QUERY_TIMEOUT_MS),
+// it is applied as a session-scoped `MAX_EXECUTION_TIME` hint so the limit
+// is enforced by MySQL itself for each statement, not just by ctx
+// cancellation.
+func (d *MySQLDriver) BeginReadOnlySnapshot(ctx context.Context) (ReadSession, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MySQL not connected")
+	}
+
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start read-only snapshot transaction: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if ms := time.Until(deadline).Milliseconds(); ms > 0 {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET SESSION MAX_EXECUTION_TIME = %d", ms)); err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("failed to set MAX_EXECUTION_TIME: %w", err)
+			}
+		}
+	}
+
+	return &mysqlReadSession{tx: tx}, nil
+}
+
+// mysqlReadSession implements ReadSession on top of a single open
+// sql.Tx holding a read-only snapshot transaction.
+type mysqlReadSession struct {
+	tx *sql.Tx
+}
+
+func (s *mysqlReadSession) ListSchemas(ctx context.Context) ([]types.Schema, error) {
+	return listMySQLSchemas(ctx, s.tx)
+}
+
+func (s *mysqlReadSession) ListTables(ctx context.Context, schema string) ([]types.Table, error) {
+	return listMySQLTables(ctx, s.tx, schema)
+}
+
+func (s *mysqlReadSession) DescribeTable(ctx context.Context, schema, table string) (*types.TableDescription, error) {
+	return describeMySQLTable(ctx, s.tx, schema, table)
+}
+
+func (s *mysqlReadSession) RunSQL(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
+	query = injectLimit(query, limit)
+	return scanSQLRows(ctx, s.tx, query, params, time.Now())
+}
+
+func (s *mysqlReadSession) ExplainQuery(ctx context.Context, query string) (*types.ExplainResult, error) {
+	startTime := time.Now()
+	var planJSON string
+	if err := s.tx.QueryRowContext(ctx, fmt.Sprintf("EXPLAIN FORMAT=JSON %s", query)).Scan(&planJSON); err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+
+	return &types.ExplainResult{
+		Query: query,
+		Plan: map[string]interface{}{
+			"format": "mysql_json",
+			"raw":    planJSON,
+		},
+		ExecutionTimeMs: time.Since(startTime).Milliseconds(),
+	}, nil
+}
+
+// Close rolls back the snapshot transaction. Safe to call once.
+func (s *mysqlReadSession) Close(ctx context.Context) error {
+	return s.tx.Rollback()
+}
+
+// RunSQLStream executes query on MySQL and returns a RowIterator that scans
+// rows on demand instead of buffering the whole result set.
+func (d *MySQLDriver) RunSQLStream(ctx context.Context, query string, params []interface{}, limit int) (RowIterator, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MySQL not connected")
+	}
+
+	query = injectLimit(query, limit)
+
+	rows, err := d.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	return &sqlRowIterator{rows: rows, columns: columns}, nil
+}
+
+// RunSQLStreamReadOnly behaves like RunSQLStream, but runs query inside a
+// transaction opened with sql.TxOptions{ReadOnly: true}, which the MySQL
+// driver issues as `START TRANSACTION READ ONLY`, so the server itself
+// refuses any write the query classifier missed. The transaction is rolled
+// back when the returned iterator is closed.
+func (d *MySQLDriver) RunSQLStreamReadOnly(ctx context.Context, query string, params []interface{}, limit int) (RowIterator, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MySQL not connected")
+	}
+
+	query = injectLimit(query, limit)
+
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, params...)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	return &txRowIterator{sqlRowIterator: sqlRowIterator{rows: rows, columns: columns}, tx: tx}, nil
+}
+
+// WithAdvisoryLock takes a session-level MySQL GET_LOCK on a single
+// connection pulled out of the pool, runs fn, then releases it on that
+// same connection before returning it to the pool.
+func (d *MySQLDriver) WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) error {
+	if d.db == nil {
+		return fmt.Errorf("MySQL not connected")
+	}
+
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	lockName := fmt.Sprintf("migrate:%d", key)
+	var got sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 10)", lockName).Scan(&got); err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !got.Valid || got.Int64 != 1 {
+		return fmt.Errorf("failed to acquire lock %s: not granted within 10s", lockName)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", lockName)
+
+	return fn(ctx)
+}
+
+// ExplainQuery explains a SQL query on MySQL
+// ExplainQuery returns both the classic tabular EXPLAIN (the plan shape
+// most tools and humans expect) and EXPLAIN FORMAT=JSON (cost/access-path
+// detail the tabular form doesn't carry), so a caller gets the readable
+// form without losing the detailed one.
+func (d *MySQLDriver) ExplainQuery(ctx context.Context, query string) (*types.ExplainResult, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MySQL not connected")
+	}
+
+	startTime := time.Now()
+
+	classicRows, err := explainClassic(ctx, d.db, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var planJSON string
+	if err := d.db.QueryRowContext(ctx, fmt.Sprintf("EXPLAIN FORMAT=JSON %s", query)).Scan(&planJSON); err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	executionTime := time.Since(startTime)
+
+	return &types.ExplainResult{
+		Query: query,
+		Plan: map[string]interface{}{
+			"format": "mysql",
+			"rows":   classicRows,
+			"json":   planJSON,
+		},
+		ExecutionTimeMs: executionTime.Milliseconds(),
+	}, nil
+}
+
+// explainClassic runs the tabular EXPLAIN statement and returns each
+// result row as a column-name -> value map, the same shape scanSQLRows
+// uses elsewhere so callers don't need a MySQL-specific row type.
+func explainClassic(ctx context.Context, q sqlRowsQuerier, query string) ([]map[string]interface{}, error) {
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("EXPLAIN %s", query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read EXPLAIN columns: %w", err)
+	}
+
+	var result []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		valuePointers := make([]interface{}, len(columns))
+		for i := range values {
+			valuePointers[i] = &values[i]
+		}
+		if err := rows.Scan(valuePointers...); err != nil {
+			return nil, fmt.Errorf("failed to scan EXPLAIN row: %w", err)
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			row[col] = values[i]
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating EXPLAIN rows: %w", err)
+	}
+	return result, nil
+}
+
+// Estimate runs the tabular EXPLAIN for query before it executes, sums the
+// optimizer's rows estimate over every plan row, and flags a full table
+// scan when any plan row uses no index (key is NULL). No cost is read.
+func (d *MySQLDriver) Estimate(ctx context.Context, query string) (*estimator.Estimate, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("MySQL not connected")
+	}
+
+	rows, err := d.db.QueryContext(ctx, fmt.Sprintf("EXPLAIN %s", query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, _ := rows.Columns()
+	est := &estimator.Estimate{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		valuePointers := make([]interface{}, len(columns))
+		for i := range values {
+			valuePointers[i] = &values[i]
+		}
+		if err := rows.Scan(valuePointers...); err != nil {
+			return nil, fmt.Errorf("failed to scan explain row: %w", err)
+		}
+		for i, col := range columns {
+			switch strings.ToLower(col) {
+			case "rows":
+				if n, ok := toUint64(values[i]); ok {
+					est.EstimatedRows += n
+				}
+			case "key":
+				if values[i] == nil {
+					est.FullTableScan = true
+				}
+			}
+		}
+	}
+
+	return est, nil
+}
+
+// SchemaFingerprint hashes every table's UPDATE_TIME and TABLE_ROWS from
+// information_schema, which MySQL/MariaDB bump on DDL and (for most
+// storage engines) committed DML, so the fingerprint changes whenever a
+// referenced table's definition or data changes.
+func (d *MySQLDriver) SchemaFingerprint(ctx context.Context) (string, error) {
+	if d.db == nil {
+		return "", fmt.Errorf("MySQL not connected")
+	}
+
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT table_schema, table_name, COALESCE(update_time, '1970-01-01 00:00:00'), table_rows
+		FROM information_schema.tables
+		WHERE table_schema NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys')
+		ORDER BY table_schema, table_name`)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute schema fingerprint: %w", err)
+	}
+	defer rows.Close()
+
+	h := sha256.New()
+	for rows.Next() {
+		var schema, table, updateTime string
+		var tableRows int64
+		if err := rows.Scan(&schema, &table, &updateTime, &tableRows); err != nil {
+			return "", fmt.Errorf("failed to scan schema fingerprint row: %w", err)
+		}
+		fmt.Fprintf(h, "%s.%s:%s:%d;", schema, table, updateTime, tableRows)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("error iterating schema fingerprint rows: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// GetType returns the database type
+func (d *MySQLDriver) GetType() DatabaseType {
+	return DatabaseTypeMySQL
+}
+
+// GetVersion returns the MySQL version
+func (d *MySQLDriver) GetVersion(ctx context.Context) (string, error) {
+	if d.db == nil {
+		return "MySQL (not connected)", nil
+	}
+
+	var version string
+	if err := d.db.QueryRowContext(ctx, "SELECT version()").Scan(&version); err != nil {
+		return "MySQL (version unknown)", nil
+	}
+	return fmt.Sprintf("MySQL %s", version), nil
+}
+
+// toUint64 coerces the interface{} values database/sql returns (int64,
+// []byte, string) into a uint64, used when reading EXPLAIN output whose
+// column types vary by driver.
+func toUint64(v interface{}) (uint64, bool) {
+	switch n := v.(type) {
+	case int64:
+		if n < 0 {
+			return 0, false
+		}
+		return uint64(n), true
+	case []byte:
+		var out uint64
+		if _, err := fmt.Sscanf(string(n), "%d", &out); err == nil {
+			return out, true
+		}
+	case string:
+		var out uint64
+		if _, err := fmt.Sscanf(n, "%d", &out); err == nil {
+			return out, true
+		}
+	}
+	return 0, false
+}
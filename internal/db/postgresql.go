@@ -2,20 +2,25 @@ package db
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
+	"database-mcp/internal/safety/estimator"
 	"database-mcp/internal/types"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // PostgreSQLDriver implements DatabaseDriver for PostgreSQL
 type PostgreSQLDriver struct {
-	pool *pgxpool.Pool
-	dsn  string
+	pool     *pgxpool.Pool
+	dsn      string
+	geoTypes pgGeoTypeOIDs
 }
 
 // NewPostgreSQLDriver creates a new PostgreSQL driver
@@ -74,12 +79,24 @@ func (d *PostgreSQLDriver) Ping(ctx context.Context) error {
 	return d.pool.Ping(ctx)
 }
 
+// pgQuerier is satisfied by both *pgxpool.Pool and *pgxpool.Conn, so the
+// schema introspection methods (ListSchemas, ListTables, DescribeTable) can
+// run against either the driver's pool or the single connection behind a
+// ReadSession.
+type pgQuerier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
 // ListSchemas lists all PostgreSQL schemas
 func (d *PostgreSQLDriver) ListSchemas(ctx context.Context) ([]types.Schema, error) {
 	if d.pool == nil {
 		return nil, fmt.Errorf("PostgreSQL not connected")
 	}
+	return listPgSchemas(ctx, d.pool)
+}
 
+func listPgSchemas(ctx context.Context, q pgQuerier) ([]types.Schema, error) {
 	query := `
 		SELECT 
 			schema_name,
@@ -91,7 +108,7 @@ func (d *PostgreSQLDriver) ListSchemas(ctx context.Context) ([]types.Schema, err
 		  AND schema_name NOT LIKE 'pg_toast_temp_%'
 		ORDER BY schema_name`
 
-	rows, err := d.pool.Query(ctx, query)
+	rows, err := q.Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query schemas: %w", err)
 	}
@@ -118,7 +135,10 @@ func (d *PostgreSQLDriver) ListTables(ctx context.Context, schema string) ([]typ
 	if d.pool == nil {
 		return nil, fmt.Errorf("PostgreSQL not connected")
 	}
+	return listPgTables(ctx, d.pool, schema)
+}
 
+func listPgTables(ctx context.Context, q pgQuerier, schema string) ([]types.Table, error) {
 	query := `
 		SELECT 
 			t.table_name,
@@ -140,7 +160,7 @@ func (d *PostgreSQLDriver) ListTables(ctx context.Context, schema string) ([]typ
 		WHERE t.table_schema = $1
 		ORDER BY t.table_name`
 
-	rows, err := d.pool.Query(ctx, query, schema)
+	rows, err := q.Query(ctx, query, schema)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tables: %w", err)
 	}
@@ -169,7 +189,10 @@ func (d *PostgreSQLDriver) DescribeTable(ctx context.Context, schema, table stri
 	if d.pool == nil {
 		return nil, fmt.Errorf("PostgreSQL not connected")
 	}
+	return describePgTable(ctx, d.pool, schema, table)
+}
 
+func describePgTable(ctx context.Context, q pgQuerier, schema, table string) (*types.TableDescription, error) {
 	// Get table info
 	tableQuery := `
 		SELECT 
@@ -193,7 +216,7 @@ func (d *PostgreSQLDriver) DescribeTable(ctx context.Context, schema, table stri
 
 	var desc types.TableDescription
 	var rowCount *int64
-	err := d.pool.QueryRow(ctx, tableQuery, schema, table).Scan(
+	err := q.QueryRow(ctx, tableQuery, schema, table).Scan(
 		&desc.Schema, &desc.Name, &desc.Type, &desc.Description, &rowCount,
 	)
 	if err != nil {
@@ -241,13 +264,13 @@ func (d *PostgreSQLDriver) DescribeTable(ctx context.Context, schema, table stri
 		LEFT JOIN (
 			SELECT c.column_name
 			FROM information_schema.columns c
-			WHERE c.table_schema = $1 AND c.table_name = $2 
-			  AND c.udt_name = 'geometry'
+			WHERE c.table_schema = $1 AND c.table_name = $2
+			  AND c.udt_name IN ('geometry', 'geography')
 		) geo ON geo.column_name = c.column_name
 		WHERE c.table_schema = $1 AND c.table_name = $2
 		ORDER BY c.ordinal_position`
 
-	rows, err := d.pool.Query(ctx, columnQuery, schema, table)
+	rows, err := q.Query(ctx, columnQuery, schema, table)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query columns: %w", err)
 	}
@@ -290,7 +313,7 @@ func (d *PostgreSQLDriver) DescribeTable(ctx context.Context, schema, table stri
 		GROUP BY i.relname, ix.indisunique, am.amname
 		ORDER BY i.relname`
 
-	indexRows, err := d.pool.Query(ctx, indexQuery, schema, table)
+	indexRows, err := q.Query(ctx, indexQuery, schema, table)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query indexes: %w", err)
 	}
@@ -309,8 +332,11 @@ func (d *PostgreSQLDriver) DescribeTable(ctx context.Context, schema, table stri
 	return &desc, nil
 }
 
-// RunSQL executes a SQL query on PostgreSQL
-func (d *PostgreSQLDriver) RunSQL(ctx context.Context, query string, limit int) (*types.QueryResult, error) {
+// RunSQL executes a SQL query on PostgreSQL, binding params to the query's
+// $1, $2, ... placeholders. Geometry/geography output columns (detected via
+// planGeoColumns) are transparently decoded into types.GeoValue instead of
+// the raw WKB bytes pgx would otherwise return for them.
+func (d *PostgreSQLDriver) RunSQL(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
 	if d.pool == nil {
 		return nil, fmt.Errorf("PostgreSQL not connected")
 	}
@@ -318,24 +344,314 @@ func (d *PostgreSQLDriver) RunSQL(ctx context.Context, query string, limit int)
 	startTime := time.Now()
 
 	// Add LIMIT if not present (basic implementation)
-	if limit > 0 && !strings.Contains(strings.ToUpper(query), " LIMIT ") {
-		query = fmt.Sprintf("%s LIMIT %d", strings.TrimRight(query, ";"), limit)
+	query = injectLimit(query, limit)
+
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	execQuery, plan := d.planGeoColumns(ctx, conn, query)
+
+	rows, err := conn.Query(ctx, execQuery, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPgxGeoRows(rows, query, startTime, plan)
+}
+
+// pgGeoTypeOIDs caches pg_type's OIDs for PostGIS's geometry/geography
+// types, looked up once per driver instance since they're stable for the
+// lifetime of a connection to a given database - run_sql shouldn't pay a
+// catalog round-trip on every call.
+type pgGeoTypeOIDs struct {
+	mu     sync.Mutex
+	byOID  map[uint32]string // OID -> typname ("geometry" or "geography")
+	loaded bool
+}
+
+func (c *pgGeoTypeOIDs) load(ctx context.Context, q pgQuerier) (map[uint32]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.loaded {
+		return c.byOID, nil
+	}
+
+	rows, err := q.Query(ctx, "SELECT oid, typname FROM pg_catalog.pg_type WHERE typname IN ('geometry', 'geography')")
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up PostGIS type OIDs: %w", err)
+	}
+	defer rows.Close()
+
+	byOID := make(map[uint32]string)
+	for rows.Next() {
+		var oid uint32
+		var typname string
+		if err := rows.Scan(&oid, &typname); err != nil {
+			return nil, fmt.Errorf("failed to scan pg_type row: %w", err)
+		}
+		byOID[oid] = typname
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pg_type rows: %w", err)
+	}
+
+	c.byOID = byOID
+	c.loaded = true
+	return byOID, nil
+}
+
+// geoColumnPlan records, for one output column of a query, whether it is a
+// PostGIS geometry/geography column and (if so) how planGeoColumns rewrote
+// it into derived columns that scanPgxGeoRows reassembles into a
+// types.GeoValue.
+type geoColumnPlan struct {
+	name     string
+	typeName string
+	isGeo    bool
+}
+
+// planGeoColumns describes query's result columns via Prepare (without
+// executing it) and, for every column whose type resolves to a PostGIS
+// geometry/geography OID, rewrites the query to additionally select its
+// WKT/GeoJSON/SRID/envelope so RunSQL can assemble a types.GeoValue in the
+// same round trip instead of a second query per value. If the statement
+// can't be described ahead of execution (e.g. DDL, multiple statements) or
+// PostGIS's catalog isn't readable, it returns query unchanged and a nil
+// plan so the caller falls back to plain scanning.
+func (d *PostgreSQLDriver) planGeoColumns(ctx context.Context, conn *pgxpool.Conn, query string) (string, []geoColumnPlan) {
+	geoTypeNames, err := d.geoTypes.load(ctx, conn)
+	if err != nil {
+		return query, nil
+	}
+
+	stmt, err := conn.Conn().Prepare(ctx, "", query)
+	if err != nil {
+		return query, nil
+	}
+
+	typeMap := conn.Conn().TypeMap()
+	plan := make([]geoColumnPlan, len(stmt.Fields))
+	hasGeo := false
+	for i, f := range stmt.Fields {
+		name := string(f.Name)
+		if typeName, ok := geoTypeNames[f.DataTypeOID]; ok {
+			plan[i] = geoColumnPlan{name: name, typeName: typeName, isGeo: true}
+			hasGeo = true
+			continue
+		}
+
+		typeName := fmt.Sprintf("oid:%d", f.DataTypeOID)
+		if pgType, ok := typeMap.TypeForOID(f.DataTypeOID); ok {
+			typeName = pgType.Name
+		}
+		plan[i] = geoColumnPlan{name: name, typeName: typeName}
+	}
+
+	if !hasGeo {
+		return query, plan
+	}
+
+	var b strings.Builder
+	b.WriteString("WITH __mcp_geo_src AS (")
+	b.WriteString(query)
+	b.WriteString(") SELECT ")
+	for i, p := range plan {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if !p.isGeo {
+			b.WriteString(quotePgIdent(p.name))
+			continue
+		}
+
+		qn := quotePgIdent(p.name)
+		fmt.Fprintf(&b,
+			"ST_AsText(%[1]s) AS %[2]s, ST_AsGeoJSON(%[1]s) AS %[3]s, ST_SRID(%[1]s) AS %[4]s, "+
+				"ST_XMin(ST_Envelope(%[1]s)) AS %[5]s, ST_YMin(ST_Envelope(%[1]s)) AS %[6]s, "+
+				"ST_XMax(ST_Envelope(%[1]s)) AS %[7]s, ST_YMax(ST_Envelope(%[1]s)) AS %[8]s",
+			qn,
+			quotePgIdent(p.name+"__wkt"),
+			quotePgIdent(p.name+"__geojson"),
+			quotePgIdent(p.name+"__srid"),
+			quotePgIdent(p.name+"__bbox_xmin"),
+			quotePgIdent(p.name+"__bbox_ymin"),
+			quotePgIdent(p.name+"__bbox_xmax"),
+			quotePgIdent(p.name+"__bbox_ymax"),
+		)
+	}
+	b.WriteString(" FROM __mcp_geo_src")
+
+	return b.String(), plan
+}
+
+// quotePgIdent double-quotes a PostgreSQL identifier, doubling any embedded
+// quote characters.
+func quotePgIdent(ident string) string {
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+// RunSQLReadOnly behaves like RunSQL, but runs query inside an explicit
+// `BEGIN READ ONLY` ... `ROLLBACK` transaction on a single acquired
+// connection, so Postgres itself refuses any write the query classifier
+// missed.
+func (d *PostgreSQLDriver) RunSQLReadOnly(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
+	if d.pool == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	query = injectLimit(query, limit)
+
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "BEGIN READ ONLY"); err != nil {
+		return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+	}
+	defer conn.Exec(ctx, "ROLLBACK")
+
+	startTime := time.Now()
+
+	rows, err := conn.Query(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPgxRows(rows, query, startTime)
+}
+
+// BeginReadOnlySnapshot opens a `BEGIN TRANSACTION READ ONLY DEFERRABLE
+// ISOLATION LEVEL REPEATABLE READ` transaction on a single acquired
+// connection, so every call made through the returned session sees the
+// same point-in-time snapshot of both the catalog and the data. If ctx
+// carries a deadline (the MCP server sets one from QUERY_TIMEOUT_MS), it
+// is applied as `SET LOCAL statement_timeout` so the limit is enforced by
+// Postgres itself for each statement run inside the session, not just by
+// ctx cancellation.
+func (d *PostgreSQLDriver) BeginReadOnlySnapshot(ctx context.Context) (ReadSession, error) {
+	if d.pool == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
 	}
 
-	rows, err := d.pool.Query(ctx, query)
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, "BEGIN TRANSACTION READ ONLY DEFERRABLE ISOLATION LEVEL REPEATABLE READ"); err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("failed to start read-only snapshot transaction: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if ms := time.Until(deadline).Milliseconds(); ms > 0 {
+			if _, err := conn.Exec(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", ms)); err != nil {
+				conn.Exec(ctx, "ROLLBACK")
+				conn.Release()
+				return nil, fmt.Errorf("failed to set statement_timeout: %w", err)
+			}
+		}
+	}
+
+	return &postgresReadSession{conn: conn}, nil
+}
+
+// postgresReadSession implements ReadSession on top of a single acquired
+// pgxpool.Conn holding an open read-only snapshot transaction.
+type postgresReadSession struct {
+	conn *pgxpool.Conn
+}
+
+func (s *postgresReadSession) ListSchemas(ctx context.Context) ([]types.Schema, error) {
+	return listPgSchemas(ctx, s.conn)
+}
+
+func (s *postgresReadSession) ListTables(ctx context.Context, schema string) ([]types.Table, error) {
+	return listPgTables(ctx, s.conn, schema)
+}
+
+func (s *postgresReadSession) DescribeTable(ctx context.Context, schema, table string) (*types.TableDescription, error) {
+	return describePgTable(ctx, s.conn, schema, table)
+}
+
+func (s *postgresReadSession) RunSQL(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
+	query = injectLimit(query, limit)
+
+	startTime := time.Now()
+	rows, err := s.conn.Query(ctx, query, params...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
 	defer rows.Close()
 
-	// Get column names
+	return scanPgxRows(rows, query, startTime)
+}
+
+func (s *postgresReadSession) ExplainQuery(ctx context.Context, query string) (*types.ExplainResult, error) {
+	startTime := time.Now()
+	explainQuery := fmt.Sprintf("EXPLAIN (FORMAT JSON, ANALYZE FALSE, VERBOSE TRUE, BUFFERS FALSE) %s", query)
+
+	var planJSON string
+	if err := s.conn.QueryRow(ctx, explainQuery).Scan(&planJSON); err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+
+	return &types.ExplainResult{
+		Query: query,
+		Plan: map[string]interface{}{
+			"format": "postgresql_json",
+			"raw":    planJSON,
+		},
+		ExecutionTimeMs: time.Since(startTime).Milliseconds(),
+	}, nil
+}
+
+// Close rolls back the snapshot transaction and releases the connection
+// back to the pool. Safe to call once.
+func (s *postgresReadSession) Close(ctx context.Context) error {
+	defer s.conn.Release()
+	_, err := s.conn.Exec(ctx, "ROLLBACK")
+	return err
+}
+
+// WithAdvisoryLock takes a session-level pg_advisory_lock on a single
+// acquired connection, runs fn, then releases it on that same connection
+// before returning it to the pool.
+func (d *PostgreSQLDriver) WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) error {
+	if d.pool == nil {
+		return fmt.Errorf("PostgreSQL not connected")
+	}
+
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+		return fmt.Errorf("failed to acquire advisory lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+
+	return fn(ctx)
+}
+
+// scanPgxRows reads every row out of rows and assembles a QueryResult,
+// shared by RunSQL and RunSQLReadOnly.
+func scanPgxRows(rows pgx.Rows, query string, startTime time.Time) (*types.QueryResult, error) {
 	fieldDescriptions := rows.FieldDescriptions()
 	columns := make([]string, len(fieldDescriptions))
 	for i, fd := range fieldDescriptions {
 		columns[i] = string(fd.Name)
 	}
 
-	// Read all rows
 	var resultRows [][]interface{}
 	for rows.Next() {
 		values, err := rows.Values()
@@ -360,6 +676,104 @@ func (d *PostgreSQLDriver) RunSQL(ctx context.Context, query string, limit int)
 	}, nil
 }
 
+// scanPgxGeoRows reads rows into a QueryResult using plan to decode each
+// PostGIS geometry/geography column (expanded by planGeoColumns into
+// several derived columns) back into a single types.GeoValue per row, and
+// to populate ColumnTypes for every column. If plan is nil (planGeoColumns
+// couldn't describe the statement ahead of execution), it falls back to
+// scanPgxRows's plain behavior.
+func scanPgxGeoRows(rows pgx.Rows, query string, startTime time.Time, plan []geoColumnPlan) (*types.QueryResult, error) {
+	if plan == nil {
+		return scanPgxRows(rows, query, startTime)
+	}
+
+	columns := make([]string, len(plan))
+	columnTypes := make([]string, len(plan))
+	for i, p := range plan {
+		columns[i] = p.name
+		columnTypes[i] = p.typeName
+	}
+
+	var resultRows [][]interface{}
+	for rows.Next() {
+		raw, err := rows.Values()
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row values: %w", err)
+		}
+
+		row := make([]interface{}, len(plan))
+		pos := 0
+		for i, p := range plan {
+			if !p.isGeo {
+				row[i] = raw[pos]
+				pos++
+				continue
+			}
+
+			geo := types.GeoValue{}
+			if s, ok := raw[pos].(string); ok {
+				geo.WKT = s
+			}
+			if s, ok := raw[pos+1].(string); ok {
+				geo.GeoJSON = s
+			}
+			if n, ok := toInt(raw[pos+2]); ok {
+				geo.SRID = n
+			}
+			xmin, okXmin := toFloat64(raw[pos+3])
+			ymin, okYmin := toFloat64(raw[pos+4])
+			xmax, okXmax := toFloat64(raw[pos+5])
+			ymax, okYmax := toFloat64(raw[pos+6])
+			if okXmin && okYmin && okXmax && okYmax {
+				geo.BBox = []float64{xmin, ymin, xmax, ymax}
+			}
+			row[i] = geo
+			pos += 7
+		}
+		resultRows = append(resultRows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return &types.QueryResult{
+		Columns:         columns,
+		ColumnTypes:     columnTypes,
+		Rows:            resultRows,
+		RowCount:        len(resultRows),
+		ExecutionTimeMs: time.Since(startTime).Milliseconds(),
+		Query:           query,
+	}, nil
+}
+
+// toInt coerces a pgx-decoded numeric value (int16/int32/int64) to int.
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int16:
+		return int(n), true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	default:
+		return 0, false
+	}
+}
+
+// toFloat64 coerces a pgx-decoded numeric value (float32/float64) to
+// float64.
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
 // ExplainQuery explains a SQL query on PostgreSQL
 func (d *PostgreSQLDriver) ExplainQuery(ctx context.Context, query string) (*types.ExplainResult, error) {
 	if d.pool == nil {
@@ -390,6 +804,250 @@ func (d *PostgreSQLDriver) ExplainQuery(ctx context.Context, query string) (*typ
 	}, nil
 }
 
+// RunSQLStream executes query on PostgreSQL and returns a RowIterator that
+// scans rows on demand instead of buffering the whole result set.
+func (d *PostgreSQLDriver) RunSQLStream(ctx context.Context, query string, params []interface{}, limit int) (RowIterator, error) {
+	if d.pool == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	query = injectLimit(query, limit)
+
+	rows, err := d.pool.Query(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	fieldDescriptions := rows.FieldDescriptions()
+	columns := make([]string, len(fieldDescriptions))
+	for i, fd := range fieldDescriptions {
+		columns[i] = string(fd.Name)
+	}
+
+	return &postgresRowIterator{rows: rows, columns: columns}, nil
+}
+
+// RunSQLStreamReadOnly behaves like RunSQLStream, but runs query inside an
+// explicit `BEGIN READ ONLY` transaction on a single acquired connection,
+// so Postgres itself refuses any write the query classifier missed. The
+// connection is held open, and the transaction rolled back, when the
+// returned iterator is closed.
+func (d *PostgreSQLDriver) RunSQLStreamReadOnly(ctx context.Context, query string, params []interface{}, limit int) (RowIterator, error) {
+	if d.pool == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	query = injectLimit(query, limit)
+
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, "BEGIN READ ONLY"); err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, query, params...)
+	if err != nil {
+		conn.Exec(ctx, "ROLLBACK")
+		conn.Release()
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	fieldDescriptions := rows.FieldDescriptions()
+	columns := make([]string, len(fieldDescriptions))
+	for i, fd := range fieldDescriptions {
+		columns[i] = string(fd.Name)
+	}
+
+	return &postgresTxRowIterator{
+		postgresRowIterator: postgresRowIterator{rows: rows, columns: columns},
+		conn:                conn,
+	}, nil
+}
+
+// postgresTxRowIterator wraps postgresRowIterator with the acquired
+// connection RunSQLStreamReadOnly opened its read-only transaction on, so
+// Close both rolls the transaction back and releases the connection back
+// to the pool.
+type postgresTxRowIterator struct {
+	postgresRowIterator
+	conn *pgxpool.Conn
+}
+
+func (it *postgresTxRowIterator) Close() error {
+	it.rows.Close()
+	it.conn.Exec(context.Background(), "ROLLBACK")
+	it.conn.Release()
+	return nil
+}
+
+// postgresRowIterator adapts pgx.Rows to the RowIterator interface.
+type postgresRowIterator struct {
+	rows    pgx.Rows
+	columns []string
+}
+
+func (it *postgresRowIterator) Columns() []string { return it.columns }
+
+func (it *postgresRowIterator) Next(ctx context.Context, batchSize int) ([][]interface{}, bool, error) {
+	batch := make([][]interface{}, 0, batchSize)
+	for len(batch) < batchSize && it.rows.Next() {
+		values, err := it.rows.Values()
+		if err != nil {
+			return batch, false, fmt.Errorf("failed to scan row values: %w", err)
+		}
+		batch = append(batch, values)
+	}
+	if err := it.rows.Err(); err != nil {
+		return batch, false, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return batch, len(batch) == batchSize, nil
+}
+
+func (it *postgresRowIterator) Close() error {
+	it.rows.Close()
+	return nil
+}
+
+// Estimate runs EXPLAIN (FORMAT JSON) without ANALYZE and reads the
+// planner's row/width/cost estimate for the top-level plan node, so the
+// guardrail can refuse an expensive query before it actually executes.
+func (d *PostgreSQLDriver) Estimate(ctx context.Context, query string) (*estimator.Estimate, error) {
+	if d.pool == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	explainQuery := fmt.Sprintf("EXPLAIN (FORMAT JSON) %s", query)
+
+	var planJSON string
+	if err := d.pool.QueryRow(ctx, explainQuery).Scan(&planJSON); err != nil {
+		return nil, fmt.Errorf("failed to estimate query: %w", err)
+	}
+
+	var raw []map[string]interface{}
+	if err := json.Unmarshal([]byte(planJSON), &raw); err != nil || len(raw) == 0 {
+		return nil, fmt.Errorf("failed to parse EXPLAIN JSON output: %w", err)
+	}
+
+	plan, _ := raw[0]["Plan"].(map[string]interface{})
+	rows, _ := plan["Plan Rows"].(float64)
+	width, _ := plan["Plan Width"].(float64)
+	cost, _ := plan["Total Cost"].(float64)
+	nodeType, _ := plan["Node Type"].(string)
+	_, hasIndex := plan["Index Name"]
+
+	return &estimator.Estimate{
+		EstimatedRows:  uint64(rows),
+		EstimatedBytes: uint64(rows) * uint64(width),
+		EstimatedCost:  cost,
+		FullTableScan:  strings.Contains(nodeType, "Seq Scan") && !hasIndex,
+	}, nil
+}
+
+// SchemaFingerprint hashes every user table's catalog row version
+// (pg_class.xmin, which changes on any DDL touching that row) alongside
+// its insert/update/delete tuple counters from pg_stat_user_tables, so the
+// fingerprint changes whenever a referenced table's definition or data
+// changes.
+func (d *PostgreSQLDriver) SchemaFingerprint(ctx context.Context) (string, error) {
+	if d.pool == nil {
+		return "", fmt.Errorf("PostgreSQL not connected")
+	}
+
+	query := `
+		SELECT COALESCE(md5(string_agg(
+			n.nspname || '.' || c.relname || ':' || c.xmin::text || ':' ||
+			COALESCE(s.n_tup_ins, 0)::text || ':' || COALESCE(s.n_tup_upd, 0)::text || ':' || COALESCE(s.n_tup_del, 0)::text,
+			',' ORDER BY n.nspname, c.relname
+		)), '')
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_catalog.pg_stat_user_tables s ON s.relid = c.oid
+		WHERE c.relkind IN ('r', 'v', 'm')
+		  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')`
+
+	var fingerprint string
+	if err := d.pool.QueryRow(ctx, query).Scan(&fingerprint); err != nil {
+		return "", fmt.Errorf("failed to compute schema fingerprint: %w", err)
+	}
+	return fingerprint, nil
+}
+
+// DescribeSpatial reports PostGIS metadata for schema.table's geometry and
+// geography columns - each one's SRID, geometry subtype, and whether it has
+// a GiST index - so a caller can write a correct spatial predicate without
+// round-tripping through EXPLAIN to discover the SRID first.
+func (d *PostgreSQLDriver) DescribeSpatial(ctx context.Context, schema, table string) ([]types.SpatialColumn, error) {
+	if d.pool == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	query := `
+		SELECT f_geometry_column, srid, type
+		FROM public.geometry_columns
+		WHERE f_table_schema = $1 AND f_table_name = $2
+		UNION ALL
+		SELECT f_geography_column, srid, type
+		FROM public.geography_columns
+		WHERE f_table_schema = $1 AND f_table_name = $2
+		ORDER BY 1`
+
+	rows, err := d.pool.Query(ctx, query, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query spatial columns (is PostGIS installed?): %w", err)
+	}
+	defer rows.Close()
+
+	var cols []types.SpatialColumn
+	for rows.Next() {
+		var c types.SpatialColumn
+		if err := rows.Scan(&c.Column, &c.SRID, &c.GeometryType); err != nil {
+			return nil, fmt.Errorf("failed to scan spatial column row: %w", err)
+		}
+		cols = append(cols, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating spatial column rows: %w", err)
+	}
+
+	gistQuery := `
+		SELECT DISTINCT a.attname
+		FROM pg_catalog.pg_index ix
+		JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_catalog.pg_class t ON t.oid = ix.indrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_catalog.pg_am am ON am.oid = i.relam
+		JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = ANY(ix.indkey)
+		WHERE n.nspname = $1 AND t.relname = $2 AND am.amname = 'gist'`
+
+	gistRows, err := d.pool.Query(ctx, gistQuery, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query GiST indexes: %w", err)
+	}
+	defer gistRows.Close()
+
+	gistColumns := make(map[string]bool)
+	for gistRows.Next() {
+		var col string
+		if err := gistRows.Scan(&col); err != nil {
+			return nil, fmt.Errorf("failed to scan GiST index row: %w", err)
+		}
+		gistColumns[col] = true
+	}
+	if err := gistRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating GiST index rows: %w", err)
+	}
+
+	for i := range cols {
+		cols[i].HasGistIndex = gistColumns[cols[i].Column]
+	}
+
+	return cols, nil
+}
+
 // GetType returns the database type
 func (d *PostgreSQLDriver) GetType() DatabaseType {
 	return DatabaseTypePostgreSQL
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BindParams resolves the SQL parameters an MCP caller supplied for run_sql
+// into a single positional arg slice and rewrites the query so its
+// placeholders match the target driver's dialect.
+//
+// args may be either a positional []interface{} (matched against `?`
+// placeholders in source order) or a map[string]interface{} of named binds
+// (matched against `:name` placeholders). Both forms are rewritten to the
+// dialect-native placeholder style returned by rebindQuery.
+func BindParams(dbType DatabaseType, query string, args interface{}) (string, []interface{}, error) {
+	switch v := args.(type) {
+	case nil:
+		return query, nil, nil
+	case []interface{}:
+		return rebindQuery(dbType, query), v, nil
+	case map[string]interface{}:
+		positional, ordered, err := bindNamedParams(query, v)
+		if err != nil {
+			return "", nil, err
+		}
+		return rebindQuery(dbType, positional), ordered, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported parameters type %T: expected array or object", args)
+	}
+}
+
+// bindNamedParams rewrites `:name` placeholders to `?` (in the order they
+// appear) and returns the matching argument slice, or a ParamError if a
+// named placeholder has no corresponding entry in named.
+func bindNamedParams(query string, named map[string]interface{}) (string, []interface{}, error) {
+	var out strings.Builder
+	var ordered []interface{}
+	var missing []string
+
+	inSingle, inDouble := false, false
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+
+		switch {
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+			out.WriteRune(c)
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+			out.WriteRune(c)
+		case c == ':' && !inSingle && !inDouble && i+1 < len(runes) && (isIdentStart(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && isIdentPart(runes[j]) {
+				j++
+			}
+			name := string(runes[i+1 : j])
+			value, ok := named[name]
+			if !ok {
+				missing = append(missing, name)
+			}
+			ordered = append(ordered, value)
+			out.WriteByte('?')
+			i = j - 1
+		default:
+			out.WriteRune(c)
+		}
+	}
+
+	if len(missing) > 0 {
+		return "", nil, &ParamError{Missing: missing}
+	}
+
+	return out.String(), ordered, nil
+}
+
+func isIdentStart(c rune) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c rune) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// rebindQuery converts `?` placeholders to the placeholder style the given
+// dialect expects (in the style of sqlx's Rebind), leaving string/identifier
+// literals untouched.
+func rebindQuery(dbType DatabaseType, query string) string {
+	style := placeholderStyle(dbType)
+	if style == bindQuestion {
+		return query
+	}
+
+	var out strings.Builder
+	inSingle, inDouble := false, false
+	n := 0
+	for _, c := range query {
+		switch {
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+			out.WriteRune(c)
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+			out.WriteRune(c)
+		case c == '?' && !inSingle && !inDouble:
+			n++
+			switch style {
+			case bindDollar:
+				out.WriteByte('$')
+				out.WriteString(strconv.Itoa(n))
+			case bindAtP:
+				out.WriteString("@p")
+				out.WriteString(strconv.Itoa(n))
+			}
+		default:
+			out.WriteRune(c)
+		}
+	}
+	return out.String()
+}
+
+type bindStyle int
+
+const (
+	bindQuestion bindStyle = iota // MySQL, ClickHouse, SQLite: ?
+	bindDollar                    // PostgreSQL: $1, $2, ...
+	bindAtP                       // MSSQL: @p1, @p2, ...
+)
+
+func placeholderStyle(dbType DatabaseType) bindStyle {
+	switch dbType {
+	case DatabaseTypePostgreSQL:
+		return bindDollar
+	case DatabaseTypeMSSQL:
+		return bindAtP
+	default:
+		return bindQuestion
+	}
+}
+
+// ParamError is returned when a parameterized query references a named
+// placeholder that the caller did not supply a value for, so the MCP layer
+// can surface exactly which binds are missing instead of a raw driver error.
+type ParamError struct {
+	Missing []string
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("missing parameter(s): %s", strings.Join(e.Missing, ", "))
+}
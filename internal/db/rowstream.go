@@ -0,0 +1,94 @@
+package db
+
+import "context"
+
+// RowStream yields a query's result rows one at a time, for callers (the
+// stream_sql MCP tool, format encoders) that want sql.Rows-style iteration
+// instead of RunSQLStream's fixed-size batches.
+type RowStream interface {
+	// Next advances to the next row, returning false once the underlying
+	// RowIterator is exhausted or ctx is cancelled. Check Err after Next
+	// returns false to distinguish exhaustion from failure.
+	Next() bool
+
+	// Values returns the current row's column values. Only valid after a
+	// Next call that returned true.
+	Values() []interface{}
+
+	// Err returns the first error encountered by Next, if any.
+	Err() error
+
+	// Close releases the underlying RowIterator. Safe to call multiple
+	// times.
+	Close() error
+}
+
+// NewRowStream adapts it into a RowStream, pulling batchSize rows at a time
+// underneath and handing them out one by one, so a caller that wants
+// per-row iteration doesn't need every driver to grow a second, redundant
+// iterator implementation alongside RowIterator.
+func NewRowStream(ctx context.Context, it RowIterator, batchSize int) RowStream {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &rowStream{ctx: ctx, it: it, batchSize: batchSize}
+}
+
+type rowStream struct {
+	ctx       context.Context
+	it        RowIterator
+	batchSize int
+
+	batch []Row
+	idx   int
+	more  bool
+	done  bool
+	err   error
+}
+
+// Row is a plain column-value slice, named so RowStream's doc comments can
+// refer to "a Row" instead of repeating "[]interface{}".
+type Row = []interface{}
+
+func (s *rowStream) Next() bool {
+	if s.err != nil || s.done {
+		return false
+	}
+	if s.idx < len(s.batch) {
+		s.idx++
+		return true
+	}
+	if !s.more && s.batch != nil {
+		s.done = true
+		return false
+	}
+
+	batch, more, err := s.it.Next(s.ctx, s.batchSize)
+	if err != nil {
+		s.err = err
+		s.done = true
+		return false
+	}
+	s.batch, s.more, s.idx = batch, more, 0
+	if len(s.batch) == 0 {
+		s.done = true
+		return false
+	}
+	s.idx = 1
+	return true
+}
+
+func (s *rowStream) Values() []interface{} {
+	if s.idx == 0 || s.idx > len(s.batch) {
+		return nil
+	}
+	return s.batch[s.idx-1]
+}
+
+func (s *rowStream) Err() error {
+	return s.err
+}
+
+func (s *rowStream) Close() error {
+	return s.it.Close()
+}
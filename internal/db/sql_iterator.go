@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"database-mcp/internal/types"
+)
+
+// sqlRowsQuerier is satisfied by both *sql.DB and *sql.Tx, so scanSQLRows
+// can buffer a result set the same way regardless of whether the caller
+// ran it in a read-only transaction.
+type sqlRowsQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// sqlQuerier extends sqlRowsQuerier with QueryRowContext, satisfied by
+// both *sql.DB and *sql.Tx. The database/sql-backed drivers' schema
+// introspection methods (ListSchemas, ListTables, DescribeTable) take one
+// so they can run against either the driver's pool or the single
+// transaction behind a ReadSession.
+type sqlQuerier interface {
+	sqlRowsQuerier
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// scanSQLRows runs query on q and buffers every row into a QueryResult,
+// shared by the database/sql-backed drivers' RunSQL and RunSQLReadOnly.
+func scanSQLRows(ctx context.Context, q sqlRowsQuerier, query string, params []interface{}, startTime time.Time) (*types.QueryResult, error) {
+	rows, err := q.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	var resultRows [][]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		valuePointers := make([]interface{}, len(columns))
+		for i := range values {
+			valuePointers[i] = &values[i]
+		}
+		if err := rows.Scan(valuePointers...); err != nil {
+			return nil, fmt.Errorf("failed to scan row values: %w", err)
+		}
+		resultRows = append(resultRows, values)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	executionTime := time.Since(startTime)
+
+	return &types.QueryResult{
+		Columns:         columns,
+		Rows:            resultRows,
+		RowCount:        len(resultRows),
+		ExecutionTimeMs: executionTime.Milliseconds(),
+		Query:           query,
+	}, nil
+}
+
+// sqlRowIterator adapts a database/sql *sql.Rows to the RowIterator
+// interface, shared by the database/sql-backed drivers (MySQL, SQLite,
+// MSSQL).
+type sqlRowIterator struct {
+	rows    *sql.Rows
+	columns []string
+}
+
+func (it *sqlRowIterator) Columns() []string { return it.columns }
+
+func (it *sqlRowIterator) Next(ctx context.Context, batchSize int) ([][]interface{}, bool, error) {
+	batch := make([][]interface{}, 0, batchSize)
+	for len(batch) < batchSize && it.rows.Next() {
+		values := make([]interface{}, len(it.columns))
+		valuePointers := make([]interface{}, len(it.columns))
+		for i := range values {
+			valuePointers[i] = &values[i]
+		}
+		if err := it.rows.Scan(valuePointers...); err != nil {
+			return batch, false, fmt.Errorf("failed to scan row values: %w", err)
+		}
+		batch = append(batch, values)
+	}
+	if err := it.rows.Err(); err != nil {
+		return batch, false, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return batch, len(batch) == batchSize, nil
+}
+
+func (it *sqlRowIterator) Close() error {
+	return it.rows.Close()
+}
+
+// txRowIterator wraps a sqlRowIterator with the *sql.Tx a read-only
+// streaming query opened its transaction on, shared by the database/sql
+// backed drivers' RunSQLStreamReadOnly. Close rolls the transaction back
+// (in addition to closing the rows) so the read-only connection is never
+// left open past the stream's lifetime.
+type txRowIterator struct {
+	sqlRowIterator
+	tx *sql.Tx
+}
+
+func (it *txRowIterator) Close() error {
+	closeErr := it.rows.Close()
+	if err := it.tx.Rollback(); err != nil {
+		return err
+	}
+	return closeErr
+}
@@ -0,0 +1,32 @@
+package db
+
+import "regexp"
+
+// fromJoinTablePattern matches the identifier following FROM or JOIN in a
+// query - good enough to tag a cached result with the tables it reads so
+// cache_invalidate can target "just the users table" without the cache
+// needing a real SQL parser. It doesn't try to resolve aliases, CTEs, or
+// subqueries, so it's a best-effort hint rather than a dependency graph.
+var fromJoinTablePattern = regexp.MustCompile(`(?i)\b(?:FROM|JOIN)\s+([a-zA-Z_][a-zA-Z0-9_]*(?:\.[a-zA-Z_][a-zA-Z0-9_]*)?)`)
+
+// referencedTables returns the distinct table identifiers (schema-qualified
+// ones kept as-is, e.g. "public.users") a query's FROM/JOIN clauses
+// mention, in first-seen order.
+func referencedTables(query string) []string {
+	matches := fromJoinTablePattern.FindAllStringSubmatch(query, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(matches))
+	tables := make([]string, 0, len(matches))
+	for _, m := range matches {
+		table := m[1]
+		if seen[table] {
+			continue
+		}
+		seen[table] = true
+		tables = append(tables, table)
+	}
+	return tables
+}
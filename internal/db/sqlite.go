@@ -0,0 +1,531 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"database-mcp/internal/safety/estimator"
+	"database-mcp/internal/types"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteSchema is the fixed, single-schema name SQLite exposes - it has no
+// concept of schemas the way PostgreSQL/MySQL do, only attached databases.
+const sqliteSchema = "main"
+
+// SQLiteDriver implements DatabaseDriver for SQLite
+type SQLiteDriver struct {
+	db   *sql.DB
+	path string
+}
+
+// NewSQLiteDriver creates a new SQLite driver
+func NewSQLiteDriver() DatabaseDriver {
+	return &SQLiteDriver{}
+}
+
+// Connect opens the SQLite database file referenced by dsn (a `sqlite://`
+// or `file:` URL).
+func (d *SQLiteDriver) Connect(ctx context.Context, dsn string) error {
+	path := strings.TrimPrefix(dsn, "sqlite://")
+	d.path = path
+
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	// SQLite only supports a single writer; a larger pool just serializes
+	// on the file lock anyway.
+	conn.SetMaxOpenConns(1)
+
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	d.db = conn
+	slog.Info("Connected to SQLite", "path", path)
+	return nil
+}
+
+// Close closes the SQLite connection
+func (d *SQLiteDriver) Close() error {
+	if d.db != nil {
+		err := d.db.Close()
+		d.db = nil
+		slog.Info("Closed SQLite connection")
+		return err
+	}
+	return nil
+}
+
+// Ping checks if the SQLite connection is alive
+func (d *SQLiteDriver) Ping(ctx context.Context) error {
+	if d.db == nil {
+		return fmt.Errorf("SQLite not connected")
+	}
+	return d.db.PingContext(ctx)
+}
+
+// ListSchemas returns SQLite's single implicit schema, "main" (plus any
+// ATTACHed databases).
+func (d *SQLiteDriver) ListSchemas(ctx context.Context) ([]types.Schema, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+	return listSQLiteSchemas(ctx, d.db)
+}
+
+func listSQLiteSchemas(ctx context.Context, q sqlQuerier) ([]types.Schema, error) {
+	rows, err := q.QueryContext(ctx, "PRAGMA database_list")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list databases: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []types.Schema
+	for rows.Next() {
+		var seq int
+		var name, file string
+		if err := rows.Scan(&seq, &name, &file); err != nil {
+			return nil, fmt.Errorf("failed to scan database row: %w", err)
+		}
+		schemas = append(schemas, types.Schema{Name: name})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating database rows: %w", err)
+	}
+
+	return schemas, nil
+}
+
+// ListTables lists tables in the given schema (SQLite attached database).
+func (d *SQLiteDriver) ListTables(ctx context.Context, schema string) ([]types.Table, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+	return listSQLiteTables(ctx, d.db, schema)
+}
+
+func listSQLiteTables(ctx context.Context, q sqlQuerier, schema string) ([]types.Table, error) {
+	if schema == "" {
+		schema = sqliteSchema
+	}
+
+	query := fmt.Sprintf(`
+		SELECT name, type
+		FROM %s.sqlite_master
+		WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%%'
+		ORDER BY name`, schema)
+
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []types.Table
+	for rows.Next() {
+		var table types.Table
+		if err := rows.Scan(&table.Name, &table.Type); err != nil {
+			return nil, fmt.Errorf("failed to scan table row: %w", err)
+		}
+		table.Schema = schema
+		tables = append(tables, table)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table rows: %w", err)
+	}
+
+	return tables, nil
+}
+
+// DescribeTable describes a SQLite table via PRAGMA table_info/index_list.
+func (d *SQLiteDriver) DescribeTable(ctx context.Context, schema, table string) (*types.TableDescription, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+	return describeSQLiteTable(ctx, d.db, schema, table)
+}
+
+func describeSQLiteTable(ctx context.Context, q sqlQuerier, schema, table string) (*types.TableDescription, error) {
+	if schema == "" {
+		schema = sqliteSchema
+	}
+
+	desc := &types.TableDescription{Schema: schema, Name: table, Type: "table"}
+
+	columnRows, err := q.QueryContext(ctx, fmt.Sprintf("PRAGMA %s.table_info(%s)", schema, quoteSQLiteIdent(table)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns: %w", err)
+	}
+	defer columnRows.Close()
+
+	for columnRows.Next() {
+		var cid int
+		var name, colType string
+		var notNull, pk int
+		var defaultValue sql.NullString
+		if err := columnRows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, fmt.Errorf("failed to scan column row: %w", err)
+		}
+		desc.Columns = append(desc.Columns, types.Column{
+			Name:         name,
+			Type:         colType,
+			Nullable:     notNull == 0,
+			DefaultValue: defaultValue.String,
+			IsPrimaryKey: pk > 0,
+		})
+	}
+	if err := columnRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating column rows: %w", err)
+	}
+	if len(desc.Columns) == 0 {
+		return nil, fmt.Errorf("table %s.%s not found", schema, table)
+	}
+
+	indexRows, err := q.QueryContext(ctx, fmt.Sprintf("PRAGMA %s.index_list(%s)", schema, quoteSQLiteIdent(table)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer indexRows.Close()
+
+	for indexRows.Next() {
+		var seq int
+		var name, origin string
+		var unique, partial int
+		if err := indexRows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			return nil, fmt.Errorf("failed to scan index row: %w", err)
+		}
+
+		infoRows, err := q.QueryContext(ctx, fmt.Sprintf("PRAGMA %s.index_info(%s)", schema, quoteSQLiteIdent(name)))
+		if err != nil {
+			continue
+		}
+		var columns []string
+		for infoRows.Next() {
+			var seqno, cid int
+			var colName string
+			if infoRows.Scan(&seqno, &cid, &colName) == nil {
+				columns = append(columns, colName)
+			}
+		}
+		infoRows.Close()
+
+		desc.Indexes = append(desc.Indexes, types.Index{
+			Name:     name,
+			Columns:  columns,
+			IsUnique: unique > 0,
+		})
+	}
+
+	return desc, nil
+}
+
+// RunSQL executes a SQL query on SQLite, binding params to the query's `?`
+// placeholders.
+func (d *SQLiteDriver) RunSQL(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	startTime := time.Now()
+
+	query = injectLimit(query, limit)
+
+	return scanSQLRows(ctx, d.db, query, params, startTime)
+}
+
+// RunSQLReadOnly behaves like RunSQL, but sets `PRAGMA query_only = ON`
+// before running and clears it afterward. SQLite's database/sql driver
+// doesn't support sql.TxOptions{ReadOnly: true}, and the pragma is safe to
+// toggle around a single query because the connection pool is capped at
+// one connection (see Connect).
+func (d *SQLiteDriver) RunSQLReadOnly(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	query = injectLimit(query, limit)
+
+	if _, err := d.db.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		return nil, fmt.Errorf("failed to enable read-only mode: %w", err)
+	}
+	defer d.db.ExecContext(ctx, "PRAGMA query_only = OFF")
+
+	startTime := time.Now()
+
+	return scanSQLRows(ctx, d.db, query, params, startTime)
+}
+
+// BeginReadOnlySnapshot opens a deferred transaction with `PRAGMA
+// query_only = ON` for its duration, so every call made through the
+// returned session sees one consistent view of the database file and
+// can't write to it even if a classifier miss let a write-shaped query
+// through. SQLite has no per-statement timeout to apply from ctx's
+// deadline; callers still get ctx cancellation via database/sql.
+func (d *SQLiteDriver) BeginReadOnlySnapshot(ctx context.Context) (ReadSession, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start snapshot transaction: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to enable read-only mode: %w", err)
+	}
+
+	return &sqliteReadSession{db: d, tx: tx}, nil
+}
+
+// WithAdvisoryLock just runs fn: SQLite's connection pool is already
+// capped at one connection (see Connect), so there's no concurrent
+// session to serialize against.
+func (d *SQLiteDriver) WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// sqliteReadSession implements ReadSession on top of a single open sql.Tx
+// with `PRAGMA query_only = ON` set for its duration. SQLite's connection
+// pool is capped at one (see Connect), so this tx holds the database's
+// only connection until Close.
+type sqliteReadSession struct {
+	db *SQLiteDriver
+	tx *sql.Tx
+}
+
+func (s *sqliteReadSession) ListSchemas(ctx context.Context) ([]types.Schema, error) {
+	return listSQLiteSchemas(ctx, s.tx)
+}
+
+func (s *sqliteReadSession) ListTables(ctx context.Context, schema string) ([]types.Table, error) {
+	return listSQLiteTables(ctx, s.tx, schema)
+}
+
+func (s *sqliteReadSession) DescribeTable(ctx context.Context, schema, table string) (*types.TableDescription, error) {
+	return describeSQLiteTable(ctx, s.tx, schema, table)
+}
+
+func (s *sqliteReadSession) RunSQL(ctx context.Context, query string, params []interface{}, limit int) (*types.QueryResult, error) {
+	query = injectLimit(query, limit)
+	return scanSQLRows(ctx, s.tx, query, params, time.Now())
+}
+
+func (s *sqliteReadSession) ExplainQuery(ctx context.Context, query string) (*types.ExplainResult, error) {
+	startTime := time.Now()
+	rows, err := s.tx.QueryContext(ctx, fmt.Sprintf("EXPLAIN QUERY PLAN %s", query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	defer rows.Close()
+
+	var planLines []string
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if rows.Scan(&id, &parent, &notUsed, &detail) == nil {
+			planLines = append(planLines, detail)
+		}
+	}
+
+	return &types.ExplainResult{
+		Query: query,
+		Plan: map[string]interface{}{
+			"format": "sqlite_query_plan",
+			"steps":  planLines,
+		},
+		ExecutionTimeMs: time.Since(startTime).Milliseconds(),
+	}, nil
+}
+
+// Close disables query_only mode and rolls back the snapshot transaction.
+// Safe to call once.
+func (s *sqliteReadSession) Close(ctx context.Context) error {
+	err := s.tx.Rollback()
+	s.db.db.ExecContext(ctx, "PRAGMA query_only = OFF")
+	return err
+}
+
+// RunSQLStream executes query on SQLite and returns a RowIterator that
+// scans rows on demand instead of buffering the whole result set.
+func (d *SQLiteDriver) RunSQLStream(ctx context.Context, query string, params []interface{}, limit int) (RowIterator, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	query = injectLimit(query, limit)
+
+	rows, err := d.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	return &sqlRowIterator{rows: rows, columns: columns}, nil
+}
+
+// RunSQLStreamReadOnly behaves like RunSQLStream, but sets `PRAGMA
+// query_only = ON` before running, the same way RunSQLReadOnly does, and
+// clears it when the returned iterator is closed rather than immediately
+// after the query - the pragma has to stay in effect for the iterator's
+// whole lifetime since rows are still being scanned on demand.
+func (d *SQLiteDriver) RunSQLStreamReadOnly(ctx context.Context, query string, params []interface{}, limit int) (RowIterator, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	query = injectLimit(query, limit)
+
+	if _, err := d.db.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		return nil, fmt.Errorf("failed to enable read-only mode: %w", err)
+	}
+
+	rows, err := d.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		d.db.ExecContext(ctx, "PRAGMA query_only = OFF")
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		d.db.ExecContext(ctx, "PRAGMA query_only = OFF")
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	return &sqliteReadOnlyStreamIterator{
+		sqlRowIterator: sqlRowIterator{rows: rows, columns: columns},
+		db:             d,
+	}, nil
+}
+
+// sqliteReadOnlyStreamIterator wraps sqlRowIterator, clearing the
+// `query_only` pragma RunSQLStreamReadOnly set when the iterator is closed.
+type sqliteReadOnlyStreamIterator struct {
+	sqlRowIterator
+	db *SQLiteDriver
+}
+
+func (it *sqliteReadOnlyStreamIterator) Close() error {
+	err := it.rows.Close()
+	it.db.db.ExecContext(context.Background(), "PRAGMA query_only = OFF")
+	return err
+}
+
+// ExplainQuery explains a SQL query on SQLite via EXPLAIN QUERY PLAN
+func (d *SQLiteDriver) ExplainQuery(ctx context.Context, query string) (*types.ExplainResult, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	startTime := time.Now()
+
+	rows, err := d.db.QueryContext(ctx, fmt.Sprintf("EXPLAIN QUERY PLAN %s", query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	defer rows.Close()
+
+	var planLines []string
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if rows.Scan(&id, &parent, &notUsed, &detail) == nil {
+			planLines = append(planLines, detail)
+		}
+	}
+
+	executionTime := time.Since(startTime)
+
+	return &types.ExplainResult{
+		Query: query,
+		Plan: map[string]interface{}{
+			"format": "sqlite_query_plan",
+			"steps":  planLines,
+		},
+		ExecutionTimeMs: executionTime.Milliseconds(),
+	}, nil
+}
+
+// Estimate runs EXPLAIN QUERY PLAN and flags a full-table scan (a "SCAN"
+// step with no accompanying index), since SQLite's query plan doesn't
+// report row/byte estimates directly.
+func (d *SQLiteDriver) Estimate(ctx context.Context, query string) (*estimator.Estimate, error) {
+	plan, err := d.ExplainQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	est := &estimator.Estimate{}
+	steps, _ := plan.Plan["steps"].([]string)
+	for _, step := range steps {
+		if strings.Contains(step, "SCAN") && !strings.Contains(step, "USING INDEX") {
+			est.FullTableScan = true
+		}
+	}
+	return est, nil
+}
+
+// SchemaFingerprint combines SQLite's schema_version (bumped by any DDL)
+// with its data_version (bumped whenever the database file is modified by
+// any connection, including plain DML), so the fingerprint changes on
+// either a schema or a data change. SQLite exposes no per-table
+// modification counters, so this can't narrow invalidation to the tables a
+// query actually touches the way the other drivers do.
+func (d *SQLiteDriver) SchemaFingerprint(ctx context.Context) (string, error) {
+	if d.db == nil {
+		return "", fmt.Errorf("SQLite not connected")
+	}
+
+	var schemaVersion, dataVersion int64
+	if err := d.db.QueryRowContext(ctx, "PRAGMA schema_version").Scan(&schemaVersion); err != nil {
+		return "", fmt.Errorf("failed to read schema_version: %w", err)
+	}
+	if err := d.db.QueryRowContext(ctx, "PRAGMA data_version").Scan(&dataVersion); err != nil {
+		return "", fmt.Errorf("failed to read data_version: %w", err)
+	}
+
+	return fmt.Sprintf("%d:%d", schemaVersion, dataVersion), nil
+}
+
+// GetType returns the database type
+func (d *SQLiteDriver) GetType() DatabaseType {
+	return DatabaseTypeSQLite
+}
+
+// GetVersion returns the SQLite version
+func (d *SQLiteDriver) GetVersion(ctx context.Context) (string, error) {
+	if d.db == nil {
+		return "SQLite (not connected)", nil
+	}
+
+	var version string
+	if err := d.db.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&version); err != nil {
+		return "SQLite (version unknown)", nil
+	}
+	return fmt.Sprintf("SQLite %s", version), nil
+}
+
+// quoteSQLiteIdent double-quotes an identifier for interpolation into a
+// PRAGMA statement, which (unlike ordinary queries) cannot bind its
+// arguments as parameters.
+func quoteSQLiteIdent(ident string) string {
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
@@ -0,0 +1,50 @@
+package macro
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFile parses the macro definitions file at path. An empty path returns
+// no macros and no error, so MACROS_FILE can be left unset.
+func LoadFile(path string) ([]Macro, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".hcl" {
+		return nil, fmt.Errorf("macro: HCL macro files are not yet supported, define %s as YAML instead", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("macro: failed to read %s: %w", path, err)
+	}
+
+	var doc struct {
+		Macros []Macro `yaml:"macros"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("macro: failed to parse %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool, len(doc.Macros))
+	for _, m := range doc.Macros {
+		if m.Name == "" {
+			return nil, fmt.Errorf("macro: entry in %s is missing a name", path)
+		}
+		if seen[m.Name] {
+			return nil, fmt.Errorf("macro: duplicate macro name %q in %s", m.Name, path)
+		}
+		seen[m.Name] = true
+		if m.SQL == "" {
+			return nil, fmt.Errorf("macro: %q in %s has no sql template", m.Name, path)
+		}
+	}
+
+	return doc.Macros, nil
+}
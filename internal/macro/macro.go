@@ -0,0 +1,72 @@
+// Package macro loads operator-curated, named SQL queries ("macros") from
+// a YAML file and exposes them so the MCP layer can publish one tool per
+// macro, letting a client run a pre-approved query without raw run_sql
+// access.
+package macro
+
+// Macro is one named, parameterized SQL template published as its own MCP
+// tool.
+type Macro struct {
+	Name        string  `yaml:"name"`
+	Description string  `yaml:"description"`
+	Database    string  `yaml:"database"`
+	SQL         string  `yaml:"sql"`
+	Params      []Param `yaml:"params"`
+	// ReadOnly forces this macro through RunSQLReadOnly even when the
+	// server as a whole is configured with READ_ONLY=false, for macros an
+	// operator wants to guarantee can never write regardless of global config.
+	ReadOnly bool `yaml:"readonly"`
+}
+
+// Param describes one named, typed argument a macro's SQL template accepts
+// via {{.Name}}.
+type Param struct {
+	Name        string      `yaml:"name"`
+	Type        string      `yaml:"type"` // "string", "integer", "number", "boolean"
+	Description string      `yaml:"description"`
+	Required    bool        `yaml:"required"`
+	Default     interface{} `yaml:"default"`
+}
+
+// InitMacroName is reserved: a macro with this name is run once against its
+// target database right after Manager.Connect succeeds, for warmup queries,
+// and is never published as a callable tool.
+const InitMacroName = "_init"
+
+// InputSchema builds the JSON Schema ListTools publishes as this macro's
+// tool InputSchema.
+func (m Macro) InputSchema() map[string]interface{} {
+	properties := make(map[string]interface{}, len(m.Params))
+	var required []string
+	for _, p := range m.Params {
+		prop := map[string]interface{}{"type": jsonSchemaType(p.Type)}
+		if p.Description != "" {
+			prop["description"] = p.Description
+		}
+		if p.Default != nil {
+			prop["default"] = p.Default
+		}
+		properties[p.Name] = prop
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func jsonSchemaType(t string) string {
+	switch t {
+	case "integer", "number", "boolean", "array", "object":
+		return t
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,47 @@
+package macro
+
+// Registry holds the macros loaded from MACROS_FILE, keyed by name, with
+// the reserved _init macro (if any) split out separately so it's never
+// handed back by All or Get.
+type Registry struct {
+	byName map[string]Macro
+	init   *Macro
+}
+
+// NewRegistry builds a Registry from macros as loaded by LoadFile.
+func NewRegistry(macros []Macro) *Registry {
+	r := &Registry{byName: make(map[string]Macro, len(macros))}
+	for _, m := range macros {
+		if m.Name == InitMacroName {
+			init := m
+			r.init = &init
+			continue
+		}
+		r.byName[m.Name] = m
+	}
+	return r
+}
+
+// Get returns the callable macro named name, if any.
+func (r *Registry) Get(name string) (Macro, bool) {
+	m, ok := r.byName[name]
+	return m, ok
+}
+
+// All returns every callable macro (excluding _init), for ListTools to
+// publish as MCP tools.
+func (r *Registry) All() []Macro {
+	out := make([]Macro, 0, len(r.byName))
+	for _, m := range r.byName {
+		out = append(out, m)
+	}
+	return out
+}
+
+// Init returns the reserved _init macro, if the loaded file defined one.
+func (r *Registry) Init() (Macro, bool) {
+	if r.init == nil {
+		return Macro{}, false
+	}
+	return *r.init, true
+}
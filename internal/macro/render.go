@@ -0,0 +1,99 @@
+package macro
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs is the whitelist of text/template functions a macro's SQL
+// template can call - plain string helpers, nothing that touches the
+// filesystem, environment, or network, since the macro file a template
+// comes from may not have been authored by whoever is running this server.
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+}
+
+// bindRef is what a macro's SQL template sees for a declared parameter: it
+// renders to a ":name" bind placeholder - the same named-parameter syntax
+// run_sql already accepts - rather than the parameter's literal value, so a
+// template author writing {{.id}} gets a safely bound query instead of a
+// caller-supplied value spliced straight into SQL text.
+type bindRef string
+
+func (b bindRef) String() string { return string(b) }
+
+// Render validates args against m's declared parameters (applying defaults
+// and requiring any parameter marked required), then executes m's SQL
+// template. The returned query contains ":name" placeholders in place of
+// every referenced parameter; the returned named map supplies their actual
+// values and should be passed to Manager.RunSQL/RunSQLReadOnly exactly as
+// run_sql's own "parameters" argument is, so it goes through the same
+// db.BindParams rewriting and escaping.
+func Render(m Macro, args map[string]interface{}) (string, map[string]interface{}, error) {
+	data := make(map[string]interface{}, len(m.Params))
+	named := make(map[string]interface{}, len(m.Params))
+
+	for _, p := range m.Params {
+		v, ok := args[p.Name]
+		if !ok {
+			switch {
+			case p.Default != nil:
+				v = p.Default
+			case p.Required:
+				return "", nil, fmt.Errorf("macro: missing required parameter %q", p.Name)
+			default:
+				continue
+			}
+		}
+		if err := validateType(p, v); err != nil {
+			return "", nil, err
+		}
+		data[p.Name] = bindRef(":" + p.Name)
+		named[p.Name] = v
+	}
+
+	tmpl, err := template.New(m.Name).Funcs(templateFuncs).Parse(m.SQL)
+	if err != nil {
+		return "", nil, fmt.Errorf("macro: failed to parse template for %q: %w", m.Name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", nil, fmt.Errorf("macro: failed to render %q: %w", m.Name, err)
+	}
+
+	return buf.String(), named, nil
+}
+
+// validateType checks v against p's declared JSON Schema-style type,
+// catching an obviously wrong argument (e.g. a string where the macro
+// declared integer) before it reaches SQL.
+func validateType(p Param, v interface{}) error {
+	switch p.Type {
+	case "integer":
+		switch v.(type) {
+		case int, int32, int64, float64:
+		default:
+			return fmt.Errorf("macro: parameter %q must be an integer", p.Name)
+		}
+	case "number":
+		switch v.(type) {
+		case int, int32, int64, float64:
+		default:
+			return fmt.Errorf("macro: parameter %q must be a number", p.Name)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("macro: parameter %q must be a boolean", p.Name)
+		}
+	case "", "string":
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("macro: parameter %q must be a string", p.Name)
+		}
+	}
+	return nil
+}
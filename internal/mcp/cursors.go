@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"database-mcp/internal/db"
+)
+
+var (
+	errTooManyCursors = errors.New("too many open cursors, call fetch_next on an existing one or wait for it to finish")
+	errUnknownCursor  = errors.New("unknown or expired cursor")
+)
+
+func formatCursorID(n uint64) string {
+	return fmt.Sprintf("cur_%d", n)
+}
+
+// cursor tracks an open RunSQLStream iterator between a run_sql_stream call
+// and the fetch_next calls that page through it.
+type cursor struct {
+	iterator  db.RowIterator
+	database  string
+	expiresAt time.Time
+}
+
+// cursorRegistry is a per-session map of open streaming cursors, with TTL
+// eviction so an LLM client that never calls fetch_next again doesn't leak
+// a driver connection forever.
+type cursorRegistry struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxOpen int
+	cursors map[string]*cursor
+	nextID  uint64
+}
+
+func newCursorRegistry(ttl time.Duration, maxOpen int) *cursorRegistry {
+	return &cursorRegistry{
+		ttl:     ttl,
+		maxOpen: maxOpen,
+		cursors: make(map[string]*cursor),
+	}
+}
+
+// sweep evicts expired cursors, closing their iterators. Called lazily on
+// every registry access rather than from a background goroutine.
+func (r *cursorRegistry) sweep() {
+	now := time.Now()
+	for id, c := range r.cursors {
+		if now.After(c.expiresAt) {
+			c.iterator.Close()
+			delete(r.cursors, id)
+		}
+	}
+}
+
+// open registers a new iterator and returns its cursor token, or an error
+// if the registry is already at MaxConcurrentCursors.
+func (r *cursorRegistry) open(database string, it db.RowIterator) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sweep()
+	if r.maxOpen > 0 && len(r.cursors) >= r.maxOpen {
+		return "", errTooManyCursors
+	}
+
+	r.nextID++
+	id := formatCursorID(r.nextID)
+	r.cursors[id] = &cursor{
+		iterator:  it,
+		database:  database,
+		expiresAt: time.Now().Add(r.ttl),
+	}
+	return id, nil
+}
+
+// get returns the cursor for id and refreshes its TTL, or errUnknownCursor
+// if it doesn't exist (including if it already expired and was swept).
+func (r *cursorRegistry) get(id string) (*cursor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sweep()
+	c, ok := r.cursors[id]
+	if !ok {
+		return nil, errUnknownCursor
+	}
+	c.expiresAt = time.Now().Add(r.ttl)
+	return c, nil
+}
+
+// close evicts and closes a cursor, typically once its iterator reports it
+// has no more rows.
+func (r *cursorRegistry) close(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.cursors[id]; ok {
+		c.iterator.Close()
+		delete(r.cursors, id)
+	}
+}
@@ -2,17 +2,45 @@ package mcp
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
+
+	"database-mcp/internal/db"
+	"database-mcp/internal/db/builder"
+	"database-mcp/internal/macro"
+	"database-mcp/internal/mcp/streamformat"
+	"database-mcp/internal/migrate"
+	"database-mcp/internal/safety/classifier"
+	"database-mcp/internal/types"
 )
 
 // Tool handler methods for the MCP server
 
+// withCacheOverride attaches a db.CacheOverride to ctx from a tool call's
+// "cache" (bool, default true) and "cacheTtlMs" (int) arguments, so a
+// caller can bypass the result cache or ask for a shorter TTL on just this
+// one call without changing the server's CACHE_TTL_MS default.
+func withCacheOverride(ctx context.Context, args map[string]interface{}) context.Context {
+	skip := !getBoolArg(args, "cache", true)
+	ttlMs := getIntArg(args, "cacheTtlMs", 0)
+	if !skip && ttlMs == 0 {
+		return ctx
+	}
+	return db.WithCacheOverride(ctx, db.CacheOverride{
+		Skip: skip,
+		TTL:  time.Duration(ttlMs) * time.Millisecond,
+	})
+}
+
 // handleListSchemas handles the list_schemas tool call
 func (s *Server) handleListSchemas(ctx context.Context, args map[string]interface{}) (*ToolCallResult, error) {
 	database := getStringArg(args, "database", "primary")
+	ctx = withCacheOverride(ctx, args)
 
 	startTime := time.Now()
 	schemas, err := s.dbManager.ListSchemas(ctx, database)
@@ -166,11 +194,160 @@ func (s *Server) handleDescribeTable(ctx context.Context, args map[string]interf
 	}, nil
 }
 
+// handleDescribeSpatial handles the describe_spatial tool call
+func (s *Server) handleDescribeSpatial(ctx context.Context, args map[string]interface{}) (*ToolCallResult, error) {
+	database := getStringArg(args, "database", "primary")
+	schema := getStringArg(args, "schema", "")
+	table := getStringArg(args, "table", "")
+
+	if schema == "" || table == "" {
+		return &ToolCallResult{
+			Content: []ContentItem{
+				{Type: "text", Text: "Error: schema and table parameters are required"},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	startTime := time.Now()
+	columns, err := s.dbManager.DescribeSpatial(ctx, database, schema, table)
+	duration := time.Since(startTime)
+
+	if err != nil {
+		slog.Error("Failed to describe spatial columns", "database", database, "schema", schema, "table", table, "error", err)
+		return &ToolCallResult{
+			Content: []ContentItem{
+				{Type: "text", Text: fmt.Sprintf("Error describing spatial columns for %s.%s.%s: %v", database, schema, table, err)},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	result := map[string]interface{}{
+		"database":        database,
+		"columns":         columns,
+		"executionTimeMs": duration.Milliseconds(),
+	}
+
+	jsonResult, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &ToolCallResult{
+			Content: []ContentItem{
+				{Type: "text", Text: fmt.Sprintf("Error formatting result: %v", err)},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	slog.Info("Described spatial columns", "database", database, "schema", schema, "table", table, "columns", len(columns), "duration_ms", duration.Milliseconds())
+
+	return &ToolCallResult{
+		Content: []ContentItem{
+			{Type: "text", Text: string(jsonResult)},
+		},
+	}, nil
+}
+
+// tableSummary pairs one table's structure with a handful of its rows, as
+// returned by handleSummarizeSchema.
+type tableSummary struct {
+	Table      *types.TableDescription `json:"table"`
+	SampleRows *types.QueryResult      `json:"sampleRows"`
+}
+
+// handleSummarizeSchema handles the summarize_schema tool call: it opens a
+// single Manager.WithReadSnapshot and, inside it, lists schema's tables,
+// describes up to maxTables of them (or exactly the ones named in tables),
+// and samples sampleRows rows from each - all against the one point-in-time
+// view the snapshot holds open, so the table list, each description, and
+// each sample reflect the same instant instead of racing concurrent
+// writers across several separate connections.
+func (s *Server) handleSummarizeSchema(ctx context.Context, args map[string]interface{}) (*ToolCallResult, error) {
+	database := getStringArg(args, "database", "primary")
+	schema := getStringArg(args, "schema", "")
+	tables := getStringSliceArg(args, "tables")
+	maxTables := getIntArg(args, "maxTables", 5)
+	sampleRows := getIntArg(args, "sampleRows", 10)
+
+	if schema == "" {
+		return errResult("Error: schema parameter is required"), nil
+	}
+	if sampleRows > s.config.MaxRows {
+		sampleRows = s.config.MaxRows
+	}
+
+	driver, err := s.dbManager.GetDriver(database)
+	if err != nil {
+		return errResult("Error: " + err.Error()), nil
+	}
+	dialect := db.BuilderDialect(driver.GetType())
+
+	var summaries []tableSummary
+
+	startTime := time.Now()
+	err = s.dbManager.WithReadSnapshot(ctx, database, func(session db.ReadSession) error {
+		names := tables
+		if len(names) == 0 {
+			allTables, err := session.ListTables(ctx, schema)
+			if err != nil {
+				return err
+			}
+			for _, t := range allTables {
+				if len(names) >= maxTables {
+					break
+				}
+				names = append(names, t.Name)
+			}
+		}
+
+		for _, name := range names {
+			desc, err := session.DescribeTable(ctx, schema, name)
+			if err != nil {
+				return fmt.Errorf("describing %s.%s: %w", schema, name, err)
+			}
+
+			sampleSQL, sampleArgs, err := builder.Select().From(schema, name).ToSQL(dialect)
+			if err != nil {
+				return fmt.Errorf("building sample query for %s.%s: %w", schema, name, err)
+			}
+			sample, err := session.RunSQL(ctx, sampleSQL, sampleArgs, sampleRows)
+			if err != nil {
+				return fmt.Errorf("sampling %s.%s: %w", schema, name, err)
+			}
+
+			summaries = append(summaries, tableSummary{Table: desc, SampleRows: sample})
+		}
+		return nil
+	})
+	duration := time.Since(startTime)
+
+	if err != nil {
+		slog.Error("Failed to summarize schema", "database", database, "schema", schema, "error", err)
+		return errResult(fmt.Sprintf("Error summarizing schema %s.%s: %v", database, schema, err)), nil
+	}
+
+	jsonResult, err := json.MarshalIndent(map[string]interface{}{
+		"database":        database,
+		"schema":          schema,
+		"tables":          summaries,
+		"executionTimeMs": duration.Milliseconds(),
+	}, "", "  ")
+	if err != nil {
+		return errResult(fmt.Sprintf("Error formatting result: %v", err)), nil
+	}
+
+	slog.Info("Summarized schema", "database", database, "schema", schema, "tables", len(summaries), "duration_ms", duration.Milliseconds())
+
+	return &ToolCallResult{Content: []ContentItem{{Type: "text", Text: string(jsonResult)}}}, nil
+}
+
 // handleRunSQL handles the run_sql tool call
 func (s *Server) handleRunSQL(ctx context.Context, args map[string]interface{}) (*ToolCallResult, error) {
 	database := getStringArg(args, "database", "primary")
 	query := getStringArg(args, "query", "")
 	limit := getIntArg(args, "limit", 1000)
+	parameters := args["parameters"]
+	ctx = withCacheOverride(ctx, args)
 
 	if query == "" {
 		return &ToolCallResult{
@@ -186,15 +363,96 @@ func (s *Server) handleRunSQL(ctx context.Context, args map[string]interface{})
 		limit = s.config.MaxRows
 	}
 
-	// TODO: Add safety layer validation here (Phase 3)
-	// For now, we'll just log the query attempt
+	return s.executeGuardedSQL(ctx, database, query, parameters, limit, s.config.ReadOnly)
+}
+
+// classifyAndGuard classifies query and applies the run_sql cost/cardinality
+// guardrail, shared by the tools that run caller-supplied SQL (run_sql,
+// run_sql_stream, stream_sql, and macro invocations) so each gets identical
+// safety guarantees before execution. A non-nil blocked result means the
+// caller must return it immediately without running query.
+func (s *Server) classifyAndGuard(ctx context.Context, database, query string) (classification *classifier.Classification, guardrailWarning string, blocked *ToolCallResult, err error) {
+	classification, err = classifier.Classify(query)
+	if err != nil {
+		return nil, "", errResult("Error: " + err.Error()), nil
+	}
+	if !categoryAllowed(s.config.AllowedCategories, classification.Category) {
+		slog.Warn("Rejected query outside allowed categories", "database", database, "classification", classification)
+		jsonResult, _ := json.MarshalIndent(map[string]interface{}{
+			"error":          "category_not_allowed",
+			"classification": classification,
+			"allowed":        s.config.AllowedCategories,
+		}, "", "  ")
+		return classification, "", &ToolCallResult{
+			Content: []ContentItem{
+				{Type: "text", Text: string(jsonResult)},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	if s.config.GuardrailMode != GuardrailOff {
+		est, err := s.dbManager.Estimate(ctx, database, query)
+		if err != nil {
+			// Estimation is a best-effort guardrail, not a hard dependency -
+			// if the driver can't produce one, fall through to executing
+			// the query rather than blocking it on an unrelated EXPLAIN failure.
+			slog.Warn("Failed to estimate query cost, skipping guardrail", "database", database, "error", err)
+		} else if est.Exceeds(s.config.MaxEstimatedRows, s.config.MaxEstimatedBytes, s.config.MaxEstimatedCost) {
+			estJSON, _ := json.MarshalIndent(est, "", "  ")
+			if s.config.GuardrailMode == GuardrailBlock {
+				slog.Warn("Blocked expensive query", "database", database, "estimate", string(estJSON))
+				return classification, "", &ToolCallResult{
+					Content: []ContentItem{
+						{Type: "text", Text: fmt.Sprintf("Error: query refused by guardrail (estimate exceeds configured thresholds):\n%s", estJSON)},
+					},
+					IsError: true,
+				}, nil
+			}
+			guardrailWarning = fmt.Sprintf("Warning: query estimate exceeds configured thresholds:\n%s\n\n", estJSON)
+		}
+	}
+
+	return classification, guardrailWarning, nil, nil
+}
+
+// executeGuardedSQL classifies query, applies the run_sql cost/cardinality
+// guardrail, executes it (through the read-only path when readOnly is set),
+// and formats the result the same way for every caller - run_sql and macro
+// invocations alike get identical safety guarantees and response shape.
+func (s *Server) executeGuardedSQL(ctx context.Context, database, query string, parameters interface{}, limit int, readOnly bool) (*ToolCallResult, error) {
+	classification, guardrailWarning, blocked, err := s.classifyAndGuard(ctx, database, query)
+	if err != nil || blocked != nil {
+		return blocked, err
+	}
+
 	slog.Info("Executing SQL query", "database", database, "query_length", len(query), "limit", limit)
 
 	startTime := time.Now()
-	result, err := s.dbManager.RunSQL(ctx, database, query, limit)
+	var result *types.QueryResult
+	if readOnly {
+		result, err = s.dbManager.RunSQLReadOnly(ctx, database, query, parameters, limit)
+	} else {
+		result, err = s.dbManager.RunSQL(ctx, database, query, parameters, limit)
+	}
 	duration := time.Since(startTime)
 
 	if err != nil {
+		var paramErr *db.ParamError
+		if errors.As(err, &paramErr) {
+			slog.Warn("Parameter binding failed", "database", database, "missing", paramErr.Missing)
+			jsonResult, _ := json.MarshalIndent(map[string]interface{}{
+				"error":             "parameter_error",
+				"missingParameters": paramErr.Missing,
+			}, "", "  ")
+			return &ToolCallResult{
+				Content: []ContentItem{
+					{Type: "text", Text: string(jsonResult)},
+				},
+				IsError: true,
+			}, nil
+		}
+
 		slog.Error("Failed to execute SQL", "database", database, "error", err)
 		return &ToolCallResult{
 			Content: []ContentItem{
@@ -205,7 +463,10 @@ func (s *Server) handleRunSQL(ctx context.Context, args map[string]interface{})
 	}
 
 	// Format result as JSON
-	jsonResult, err := json.MarshalIndent(result, "", "  ")
+	jsonResult, err := json.MarshalIndent(map[string]interface{}{
+		"classification": classification,
+		"result":         result,
+	}, "", "  ")
 	if err != nil {
 		return &ToolCallResult{
 			Content: []ContentItem{
@@ -219,7 +480,7 @@ func (s *Server) handleRunSQL(ctx context.Context, args map[string]interface{})
 
 	return &ToolCallResult{
 		Content: []ContentItem{
-			{Type: "text", Text: string(jsonResult)},
+			{Type: "text", Text: guardrailWarning + string(jsonResult)},
 		},
 	}, nil
 }
@@ -228,6 +489,7 @@ func (s *Server) handleRunSQL(ctx context.Context, args map[string]interface{})
 func (s *Server) handleExplainSQL(ctx context.Context, args map[string]interface{}) (*ToolCallResult, error) {
 	database := getStringArg(args, "database", "primary")
 	query := getStringArg(args, "query", "")
+	ctx = withCacheOverride(ctx, args)
 
 	if query == "" {
 		return &ToolCallResult{
@@ -271,3 +533,554 @@ func (s *Server) handleExplainSQL(ctx context.Context, args map[string]interface
 		},
 	}, nil
 }
+
+// handleBuildSelect handles the build_select tool call: it renders a
+// structured query description into SQL via the builder package, validating
+// every identifier against the live schema first, and optionally executes it.
+func (s *Server) handleBuildSelect(ctx context.Context, args map[string]interface{}) (*ToolCallResult, error) {
+	database := getStringArg(args, "database", "primary")
+	schema := getStringArg(args, "schema", "")
+	table := getStringArg(args, "table", "")
+	limit := getIntArg(args, "limit", 1000)
+	execute := getBoolArg(args, "execute", true)
+
+	if schema == "" || table == "" {
+		return errResult("Error: schema and table parameters are required"), nil
+	}
+	if limit > s.config.MaxRows {
+		limit = s.config.MaxRows
+	}
+
+	desc, err := s.dbManager.DescribeTable(ctx, database, schema, table)
+	if err != nil {
+		return errResult(fmt.Sprintf("Error validating table %s.%s.%s: %v", database, schema, table, err)), nil
+	}
+	known := make(map[string]bool, len(desc.Columns))
+	for _, col := range desc.Columns {
+		known[col.Name] = true
+	}
+	validateCol := func(col string) error {
+		if !known[col] {
+			return fmt.Errorf("unknown column %q on %s.%s", col, schema, table)
+		}
+		return nil
+	}
+
+	columns := getStringSliceArg(args, "columns")
+	for _, col := range columns {
+		if err := validateCol(col); err != nil {
+			return errResult("Error: " + err.Error()), nil
+		}
+	}
+
+	sb := builder.Select(columns...).From(schema, table)
+
+	whereConds, err := parseWhereArg(args["where"], validateCol)
+	if err != nil {
+		return errResult("Error: " + err.Error()), nil
+	}
+	if len(whereConds) > 0 {
+		sb.Where(builder.And(whereConds...))
+	}
+
+	groupBy := getStringSliceArg(args, "groupBy")
+	for _, col := range groupBy {
+		if err := validateCol(col); err != nil {
+			return errResult("Error: " + err.Error()), nil
+		}
+	}
+	if len(groupBy) > 0 {
+		sb.GroupBy(groupBy...)
+	}
+
+	for _, term := range getStringSliceArg(args, "orderBy") {
+		col := term
+		descending := false
+		if trimmed, ok := strings.CutSuffix(strings.TrimSpace(term), " desc"); ok {
+			col, descending = strings.TrimSpace(trimmed), true
+		}
+		if err := validateCol(col); err != nil {
+			return errResult("Error: " + err.Error()), nil
+		}
+		sb.OrderBy(col, descending)
+	}
+
+	sb.Limit(limit)
+
+	driver, err := s.dbManager.GetDriver(database)
+	if err != nil {
+		return errResult("Error: " + err.Error()), nil
+	}
+
+	sql, sqlArgs, err := sb.ToSQL(db.BuilderDialect(driver.GetType()))
+	if err != nil {
+		return errResult("Error building query: " + err.Error()), nil
+	}
+
+	result := map[string]interface{}{
+		"database": database,
+		"sql":      sql,
+	}
+
+	if !execute {
+		jsonResult, _ := json.MarshalIndent(result, "", "  ")
+		return &ToolCallResult{Content: []ContentItem{{Type: "text", Text: string(jsonResult)}}}, nil
+	}
+
+	startTime := time.Now()
+	queryResult, err := s.dbManager.RunSQL(ctx, database, sql, sqlArgs, limit)
+	duration := time.Since(startTime)
+	if err != nil {
+		slog.Error("Failed to execute built query", "database", database, "sql", sql, "error", err)
+		return errResult(fmt.Sprintf("Error executing built query on %s database: %v", database, err)), nil
+	}
+
+	result["result"] = queryResult
+	jsonResult, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return errResult(fmt.Sprintf("Error formatting result: %v", err)), nil
+	}
+
+	slog.Info("Executed built query", "database", database, "rows", queryResult.RowCount, "duration_ms", duration.Milliseconds())
+
+	return &ToolCallResult{Content: []ContentItem{{Type: "text", Text: string(jsonResult)}}}, nil
+}
+
+// parseWhereArg converts the `where` tool argument into a slice of builder
+// Conds, validating every referenced column and restricting operators to a
+// whitelist so the resulting SQL stays safe even if the caller hallucinates
+// column names or operators.
+func parseWhereArg(raw interface{}, validateCol func(string) error) ([]builder.Cond, error) {
+	clauses, ok := raw.([]interface{})
+	if !ok {
+		if raw == nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("where must be an array of {col, op, value} objects")
+	}
+
+	var conds []builder.Cond
+	for _, item := range clauses {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("where entries must be objects")
+		}
+		col, _ := m["col"].(string)
+		op, _ := m["op"].(string)
+		if col == "" || op == "" {
+			return nil, fmt.Errorf("where entries require col and op")
+		}
+		if err := validateCol(col); err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case "eq":
+			conds = append(conds, builder.Eq(col, m["value"]))
+		case "neq":
+			conds = append(conds, builder.Neq(col, m["value"]))
+		case "like":
+			pattern, _ := m["value"].(string)
+			conds = append(conds, builder.Like(col, pattern))
+		case "isNull":
+			conds = append(conds, builder.IsNull(col))
+		case "isNotNull":
+			conds = append(conds, builder.IsNotNull(col))
+		case "in", "notIn":
+			values, ok := m["value"].([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("where op %q requires an array value", op)
+			}
+			if op == "in" {
+				conds = append(conds, builder.In(col, values...))
+			} else {
+				conds = append(conds, builder.NotIn(col, values...))
+			}
+		case "between":
+			values, ok := m["value"].([]interface{})
+			if !ok || len(values) != 2 {
+				return nil, fmt.Errorf("where op \"between\" requires a 2-element array value")
+			}
+			conds = append(conds, builder.Between(col, values[0], values[1]))
+		default:
+			return nil, fmt.Errorf("unsupported where op %q", op)
+		}
+	}
+	return conds, nil
+}
+
+// categoryAllowed reports whether category appears in allowed.
+func categoryAllowed(allowed []classifier.Category, category classifier.Category) bool {
+	for _, c := range allowed {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+func errResult(text string) *ToolCallResult {
+	return &ToolCallResult{Content: []ContentItem{{Type: "text", Text: text}}, IsError: true}
+}
+
+func getBoolArg(args map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := args[key]; ok {
+		if b, ok := val.(bool); ok {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+func getStringSliceArg(args map[string]interface{}, key string) []string {
+	val, ok := args[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(val))
+	for _, v := range val {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// handleRunSQLStream handles the run_sql_stream tool call: it opens a
+// RunSQLStream iterator, returns its first batch, and registers the
+// iterator under a cursor token for subsequent fetch_next calls.
+func (s *Server) handleRunSQLStream(ctx context.Context, args map[string]interface{}) (*ToolCallResult, error) {
+	database := getStringArg(args, "database", "primary")
+	query := getStringArg(args, "query", "")
+	limit := getIntArg(args, "limit", 1000)
+	batchSize := getIntArg(args, "batchSize", 100)
+	parameters := args["parameters"]
+
+	if query == "" {
+		return errResult("Error: query parameter is required"), nil
+	}
+	if limit > s.config.MaxRows {
+		limit = s.config.MaxRows
+	}
+
+	_, _, blocked, err := s.classifyAndGuard(ctx, database, query)
+	if err != nil || blocked != nil {
+		return blocked, err
+	}
+
+	var it db.RowIterator
+	if s.config.ReadOnly {
+		it, err = s.dbManager.RunSQLStreamReadOnly(ctx, database, query, parameters, limit)
+	} else {
+		it, err = s.dbManager.RunSQLStream(ctx, database, query, parameters, limit)
+	}
+	if err != nil {
+		slog.Error("Failed to open SQL stream", "database", database, "error", err)
+		return errResult(fmt.Sprintf("Error executing SQL on %s database: %v", database, err)), nil
+	}
+
+	batch, more, err := it.Next(ctx, batchSize)
+	if err != nil {
+		it.Close()
+		return errResult(fmt.Sprintf("Error reading query results: %v", err)), nil
+	}
+
+	result := map[string]interface{}{
+		"database": database,
+		"columns":  it.Columns(),
+		"rows":     batch,
+		"rowCount": len(batch),
+		"done":     !more,
+	}
+
+	if !more {
+		it.Close()
+	} else {
+		cursorID, err := s.cursors.open(database, it)
+		if err != nil {
+			it.Close()
+			return errResult("Error: " + err.Error()), nil
+		}
+		result["cursor"] = cursorID
+	}
+
+	jsonResult, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return errResult(fmt.Sprintf("Error formatting result: %v", err)), nil
+	}
+
+	slog.Info("Opened SQL stream", "database", database, "rows", len(batch), "more", more)
+
+	return &ToolCallResult{Content: []ContentItem{{Type: "text", Text: string(jsonResult)}}}, nil
+}
+
+// handleFetchNext handles the fetch_next tool call: it returns the next
+// batch of rows for an open run_sql_stream cursor, closing and evicting the
+// cursor once exhausted.
+func (s *Server) handleFetchNext(ctx context.Context, args map[string]interface{}) (*ToolCallResult, error) {
+	cursorID := getStringArg(args, "cursor", "")
+	batchSize := getIntArg(args, "batchSize", 100)
+
+	if cursorID == "" {
+		return errResult("Error: cursor parameter is required"), nil
+	}
+
+	c, err := s.cursors.get(cursorID)
+	if err != nil {
+		return errResult("Error: " + err.Error()), nil
+	}
+
+	batch, more, err := c.iterator.Next(ctx, batchSize)
+	if err != nil {
+		s.cursors.close(cursorID)
+		return errResult(fmt.Sprintf("Error reading query results: %v", err)), nil
+	}
+
+	result := map[string]interface{}{
+		"rows":     batch,
+		"rowCount": len(batch),
+		"done":     !more,
+	}
+
+	if !more {
+		s.cursors.close(cursorID)
+	} else {
+		result["cursor"] = cursorID
+	}
+
+	jsonResult, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return errResult(fmt.Sprintf("Error formatting result: %v", err)), nil
+	}
+
+	return &ToolCallResult{Content: []ContentItem{{Type: "text", Text: string(jsonResult)}}}, nil
+}
+
+// handleStreamSQL handles the stream_sql tool call: unlike run_sql_stream's
+// cursor/fetch_next pairing, it drains the query to completion itself
+// (still bounded by limit, enforced as a query LIMIT by the driver so a
+// rogue query can't exhaust memory) and returns the whole thing as one
+// streamformat-encoded payload, emitting a notifications/progress
+// notification every StreamProgressRows rows so a client isn't left
+// waiting silently on a multi-million-row pull.
+func (s *Server) handleStreamSQL(ctx context.Context, args map[string]interface{}) (*ToolCallResult, error) {
+	database := getStringArg(args, "database", "primary")
+	query := getStringArg(args, "query", "")
+	limit := getIntArg(args, "limit", 1000)
+	parameters := args["parameters"]
+
+	if query == "" {
+		return errResult("Error: query parameter is required"), nil
+	}
+	if limit <= 0 || limit > s.config.MaxRows {
+		limit = s.config.MaxRows
+	}
+
+	_, _, blocked, err := s.classifyAndGuard(ctx, database, query)
+	if err != nil || blocked != nil {
+		return blocked, err
+	}
+
+	var it db.RowIterator
+	if s.config.ReadOnly {
+		it, err = s.dbManager.RunSQLStreamReadOnly(ctx, database, query, parameters, limit)
+	} else {
+		it, err = s.dbManager.RunSQLStream(ctx, database, query, parameters, limit)
+	}
+	if err != nil {
+		slog.Error("Failed to open SQL stream", "database", database, "error", err)
+		return errResult(fmt.Sprintf("Error executing SQL on %s database: %v", database, err)), nil
+	}
+	stream := db.NewRowStream(ctx, it, 100)
+	defer stream.Close()
+
+	encoder, err := streamformat.New(s.config.StreamFormat)
+	if err != nil {
+		return errResult("Error: " + err.Error()), nil
+	}
+	if err := encoder.Columns(it.Columns()); err != nil {
+		return errResult(fmt.Sprintf("Error encoding columns: %v", err)), nil
+	}
+
+	progressToken := fmt.Sprintf("stream_sql-%d", s.streamTokens.Add(1))
+	notifier := NotifierFromContext(ctx)
+
+	// rowCount is capped at limit even though RunSQLStream already pushed
+	// a LIMIT into the query itself, in case the query brought its own
+	// LIMIT (larger than limit) that the driver therefore left alone.
+	rowCount := 0
+	for rowCount < limit && stream.Next() {
+		if err := encoder.Row(stream.Values()); err != nil {
+			return errResult(fmt.Sprintf("Error encoding row: %v", err)), nil
+		}
+		rowCount++
+		if s.config.StreamProgressRows > 0 && rowCount%s.config.StreamProgressRows == 0 {
+			notifier.Notify(ctx, MethodProgressNotification, ProgressParams{
+				ProgressToken: progressToken,
+				Progress:      rowCount,
+				Total:         limit,
+			})
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return errResult(fmt.Sprintf("Error reading query results: %v", err)), nil
+	}
+
+	if s.config.StreamProgressRows <= 0 || rowCount%s.config.StreamProgressRows != 0 {
+		notifier.Notify(ctx, MethodProgressNotification, ProgressParams{
+			ProgressToken: progressToken,
+			Progress:      rowCount,
+			Total:         limit,
+		})
+	}
+
+	payload, mimeType, err := encoder.Finish()
+	if err != nil {
+		return errResult(fmt.Sprintf("Error finalizing %s payload: %v", s.config.StreamFormat, err)), nil
+	}
+
+	slog.Info("Drained SQL stream", "database", database, "rows", rowCount, "format", s.config.StreamFormat)
+
+	return &ToolCallResult{Content: []ContentItem{{
+		Type:     "data",
+		Data:     base64.StdEncoding.EncodeToString(payload),
+		MimeType: mimeType,
+	}}}, nil
+}
+
+// handleMacro handles a tool call for a macro published by ListTools: it
+// validates args against the macro's declared parameters, renders its SQL
+// template, and runs the result through the same classifier/guardrail path
+// as run_sql.
+func (s *Server) handleMacro(ctx context.Context, m macro.Macro, args map[string]interface{}) (*ToolCallResult, error) {
+	query, parameters, err := macro.Render(m, args)
+	if err != nil {
+		return errResult("Error: " + err.Error()), nil
+	}
+
+	database := m.Database
+	if database == "" {
+		database = "primary"
+	}
+
+	limit := getIntArg(args, "limit", 1000)
+	if limit > s.config.MaxRows {
+		limit = s.config.MaxRows
+	}
+
+	return s.executeGuardedSQL(ctx, database, query, parameters, limit, m.ReadOnly || s.config.ReadOnly)
+}
+
+// runInitMacro runs the reserved _init macro, if one is defined, once
+// against its target database right after Connect succeeds - for warmup
+// queries (priming a cache, asserting an extension is installed) rather
+// than anything a client calls as a tool. A failure here is logged, not
+// fatal: a broken warmup query shouldn't keep the server from starting.
+func (s *Server) runInitMacro(ctx context.Context) {
+	m, ok := s.macros.Init()
+	if !ok {
+		return
+	}
+
+	query, parameters, err := macro.Render(m, nil)
+	if err != nil {
+		slog.Error("Failed to render _init macro", "error", err)
+		return
+	}
+
+	database := m.Database
+	if database == "" {
+		database = "primary"
+	}
+
+	if _, err := s.dbManager.RunSQL(ctx, database, query, parameters, s.config.MaxRows); err != nil {
+		slog.Error("Failed to run _init macro", "database", database, "error", err)
+		return
+	}
+
+	slog.Info("Ran _init macro warmup query", "database", database)
+}
+
+// handleRunMigrations handles the run_migrations tool call: it reports or
+// applies the goose-style SQL migration files loaded from MIGRATIONS_DIR
+// against the requested database.
+func (s *Server) handleRunMigrations(ctx context.Context, args map[string]interface{}) (*ToolCallResult, error) {
+	database := getStringArg(args, "database", "primary")
+	action := getStringArg(args, "action", "status")
+	steps := getIntArg(args, "steps", 0)
+
+	if s.config.ReadOnly && action != "status" {
+		slog.Warn("Rejected run_migrations in read-only mode", "database", database, "action", action)
+		return errResult(fmt.Sprintf("Error: action %q refused, server is configured read-only", action)), nil
+	}
+
+	runner := migrate.NewRunner(s.dbManager, database, s.config.MigrationsTable)
+
+	var (
+		payload interface{}
+		err     error
+	)
+	switch action {
+	case "status":
+		payload, err = runner.Status(ctx, s.migrations)
+	case "up":
+		payload, err = runner.Up(ctx, s.migrations, steps)
+	case "down":
+		payload, err = runner.Down(ctx, s.migrations, steps)
+	default:
+		return errResult(fmt.Sprintf("Error: unknown action %q, expected status, up, or down", action)), nil
+	}
+	if err != nil {
+		slog.Error("run_migrations failed", "database", database, "action", action, "error", err)
+		return errResult("Error: " + err.Error()), nil
+	}
+
+	jsonResult, err := json.MarshalIndent(map[string]interface{}{
+		"database": database,
+		"action":   action,
+		"result":   payload,
+	}, "", "  ")
+	if err != nil {
+		return errResult(fmt.Sprintf("Error formatting result: %v", err)), nil
+	}
+
+	slog.Info("Ran run_migrations", "database", database, "action", action)
+
+	return &ToolCallResult{Content: []ContentItem{{Type: "text", Text: string(jsonResult)}}}, nil
+}
+
+// handleCacheStats handles the cache_stats tool call: it reports the
+// result cache's hit/miss/eviction counters and current size.
+func (s *Server) handleCacheStats(ctx context.Context, args map[string]interface{}) (*ToolCallResult, error) {
+	stats := s.dbManager.CacheStats()
+
+	jsonResult, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return errResult(fmt.Sprintf("Error formatting result: %v", err)), nil
+	}
+
+	return &ToolCallResult{Content: []ContentItem{{Type: "text", Text: string(jsonResult)}}}, nil
+}
+
+// handleCacheInvalidate handles the cache_invalidate tool call: it evicts
+// every cached result tagged with the given database, or with a single
+// table within it when table is set.
+func (s *Server) handleCacheInvalidate(ctx context.Context, args map[string]interface{}) (*ToolCallResult, error) {
+	database := getStringArg(args, "database", "primary")
+	table := getStringArg(args, "table", "")
+
+	removed := s.dbManager.InvalidateCache(database, table)
+
+	jsonResult, err := json.MarshalIndent(map[string]interface{}{
+		"database": database,
+		"table":    table,
+		"removed":  removed,
+	}, "", "  ")
+	if err != nil {
+		return errResult(fmt.Sprintf("Error formatting result: %v", err)), nil
+	}
+
+	slog.Info("Ran cache_invalidate", "database", database, "table", table, "removed", removed)
+
+	return &ToolCallResult{Content: []ContentItem{{Type: "text", Text: string(jsonResult)}}}, nil
+}
@@ -0,0 +1,301 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HTTPTransport implements MCP over HTTP: JSON-RPC requests are POSTed to
+// /mcp, and /mcp/events is a Server-Sent Events stream a client opens
+// first to receive its session ID and any out-of-band notifications (e.g.
+// stream_sql progress) a later POST on that session triggers. Multiple
+// clients can share one process, each keeping its own session.
+type HTTPTransport struct {
+	handler   Handler
+	addr      string
+	authToken string
+
+	srv      *http.Server
+	sessions *sseSessionRegistry
+}
+
+// NewHTTPTransport creates an HTTP transport listening on addr. If
+// authToken is non-empty, every /mcp and /mcp/events request must carry
+// "Authorization: Bearer <authToken>".
+func NewHTTPTransport(handler Handler, addr, authToken string) *HTTPTransport {
+	return &HTTPTransport{
+		handler:   handler,
+		addr:      addr,
+		authToken: authToken,
+		sessions:  newSSESessionRegistry(),
+	}
+}
+
+// Start begins serving MCP over HTTP and blocks until ctx is cancelled,
+// at which point it shuts down gracefully (finishing in-flight requests)
+// before returning.
+func (t *HTTPTransport) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mcp", t.handleMCP)
+	mux.HandleFunc("/mcp/events", t.handleEvents)
+
+	t.srv = &http.Server{
+		Addr:    t.addr,
+		Handler: t.withCORS(t.withAuth(mux)),
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	slog.Info("Starting MCP HTTP transport", "addr", t.addr)
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := t.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case <-ctx.Done():
+		slog.Info("Stopping MCP HTTP transport")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := t.srv.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		return ctx.Err()
+	case err := <-errCh:
+		return err
+	}
+}
+
+// Stop shuts the HTTP server down immediately. Start's ctx-driven graceful
+// shutdown is the normal path; Stop exists to satisfy the Transport
+// interface for a caller that wants to tear the server down without
+// cancelling the context it was started with.
+func (t *HTTPTransport) Stop() error {
+	if t.srv == nil {
+		return nil
+	}
+	return t.srv.Close()
+}
+
+// withAuth requires "Authorization: Bearer <MCP_AUTH_TOKEN>" on every
+// request when an auth token is configured. A blank authToken disables
+// auth entirely (the default, matching this server's other opt-in safety
+// controls like GUARDRAIL_MODE).
+func (t *HTTPTransport) withAuth(next http.Handler) http.Handler {
+	if t.authToken == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// withCORS runs outside withAuth and already answers OPTIONS
+		// preflight requests itself, so every request reaching here is a
+		// real call that needs a valid token.
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(t.authToken)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withCORS allows browser-based MCP clients (served from an origin other
+// than this server) to call /mcp and /mcp/events.
+func (t *HTTPTransport) withCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Mcp-Session-Id")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleMCP handles POST /mcp: one JSON-RPC request body in, one JSON-RPC
+// response body out. A request carrying an "Mcp-Session-Id" header (from
+// a prior GET /mcp/events) has its Notifier routed to that session's SSE
+// stream, so long-running calls like stream_sql can report progress on
+// the event stream while this request is still pending.
+func (t *HTTPTransport) handleMCP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 32<<20))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	var req MCPRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.writeResponse(w, MCPResponse{
+			JSONRPC: "2.0",
+			Error:   &MCPError{Code: ErrorCodeParseError, Message: "Parse error", Data: err.Error()},
+		})
+		return
+	}
+	if req.JSONRPC != "2.0" {
+		t.writeResponse(w, MCPResponse{
+			JSONRPC: "2.0", ID: req.ID,
+			Error: &MCPError{Code: ErrorCodeInvalidRequest, Message: "Invalid JSON-RPC version"},
+		})
+		return
+	}
+
+	ctx := r.Context()
+	if sessionID := r.Header.Get("Mcp-Session-Id"); sessionID != "" {
+		if notifier, ok := t.sessions.notifier(sessionID); ok {
+			ctx = WithNotifier(ctx, notifier)
+			w.Header().Set("Mcp-Session-Id", sessionID)
+		}
+	}
+
+	response := dispatchRequest(ctx, t.handler, req)
+	t.writeResponse(w, response)
+}
+
+func (t *HTTPTransport) writeResponse(w http.ResponseWriter, response MCPResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("Failed to write HTTP response", "error", err)
+	}
+}
+
+// handleEvents handles GET /mcp/events: it opens a Server-Sent Events
+// stream, assigns it a fresh session ID (sent as the stream's first
+// event), and forwards every notification later POSTs on that session
+// trigger until the client disconnects or the server shuts down.
+func (t *HTTPTransport) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	sessionID, ch := t.sessions.open()
+	defer t.sessions.close(sessionID)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Mcp-Session-Id", sessionID)
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "event: session\ndata: {\"sessionId\":%q}\n\n", sessionID)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case notification, ok := <-ch:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(notification)
+			if err != nil {
+				slog.Error("Failed to marshal SSE notification", "error", err)
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+// sseSessionRegistry tracks open /mcp/events connections so a /mcp POST
+// carrying a matching Mcp-Session-Id can route its Notifier calls to the
+// right stream.
+type sseSessionRegistry struct {
+	mu       sync.Mutex
+	sessions map[string]chan MCPNotification
+}
+
+func newSSESessionRegistry() *sseSessionRegistry {
+	return &sseSessionRegistry{sessions: make(map[string]chan MCPNotification)}
+}
+
+func (r *sseSessionRegistry) open() (string, chan MCPNotification) {
+	id := newSessionID()
+	ch := make(chan MCPNotification, 16)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[id] = ch
+	return id, ch
+}
+
+// newSessionID returns a random, unguessable session token - a sequential
+// one would let any client walk small integers and attach its own POST
+// /mcp requests to another client's event stream.
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("mcp: failed to generate session id: %v", err))
+	}
+	return "sess_" + hex.EncodeToString(b)
+}
+
+func (r *sseSessionRegistry) close(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if ch, ok := r.sessions[id]; ok {
+		close(ch)
+		delete(r.sessions, id)
+	}
+}
+
+func (r *sseSessionRegistry) notifier(id string) (Notifier, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ch, ok := r.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	return &sseNotifier{ch: ch}, true
+}
+
+// sseNotifier delivers notifications to one session's SSE channel. Sends
+// are non-blocking: a session whose client has stopped reading (channel
+// full) drops the notification rather than stalling the /mcp POST that's
+// trying to report progress.
+type sseNotifier struct {
+	ch chan MCPNotification
+}
+
+func (n *sseNotifier) Notify(ctx context.Context, method string, params interface{}) error {
+	select {
+	case n.ch <- MCPNotification{JSONRPC: "2.0", Method: method, Params: params}:
+		return nil
+	default:
+		return errors.New("sse: session event channel full, notification dropped")
+	}
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// Notifier sends an out-of-band MCP notification (no id, no response)
+// while a tool call is still in flight, so a long-running call like
+// stream_sql can report progress instead of going silent until it
+// returns. Handler implementations pull one out of ctx via
+// NotifierFromContext; it's a no-op if the transport doesn't support
+// notifications.
+type Notifier interface {
+	Notify(ctx context.Context, method string, params interface{}) error
+}
+
+type notifierKey struct{}
+
+// WithNotifier returns a copy of ctx carrying n, retrievable with
+// NotifierFromContext.
+func WithNotifier(ctx context.Context, n Notifier) context.Context {
+	return context.WithValue(ctx, notifierKey{}, n)
+}
+
+// NotifierFromContext returns the Notifier attached to ctx, or a no-op
+// Notifier if none was attached (e.g. a transport that doesn't support
+// out-of-band notifications).
+func NotifierFromContext(ctx context.Context) Notifier {
+	if n, ok := ctx.Value(notifierKey{}).(Notifier); ok && n != nil {
+		return n
+	}
+	return noopNotifier{}
+}
+
+type noopNotifier struct{}
+
+func (noopNotifier) Notify(ctx context.Context, method string, params interface{}) error {
+	return nil
+}
+
+// writerNotifier writes each notification as a newline-delimited JSON-RPC
+// message to an underlying writer, guarded by a mutex since a stream_sql
+// call may emit several notifications before its final response is
+// written to the same stream.
+type writerNotifier struct {
+	mu     *sync.Mutex
+	writer io.Writer
+}
+
+func (n *writerNotifier) Notify(ctx context.Context, method string, params interface{}) error {
+	data, err := json.Marshal(MCPNotification{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, err := fmt.Fprintf(n.writer, "%s\n", data); err != nil {
+		slog.Error("Failed to write notification", "method", method, "error", err)
+		return err
+	}
+	return nil
+}
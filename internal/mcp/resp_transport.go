@@ -0,0 +1,247 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// RESPTransport implements MCP over a RESP (Redis Serialization Protocol)
+// socket, so a caller can drive tools from redis-cli or any RESP client
+// library instead of something JSON-RPC aware - useful for scripted/CLI
+// workflows that already speak RESP. It exposes a small, fixed command
+// set rather than generic JSON-RPC passthrough:
+//
+//	PING [message]                - connectivity check
+//	TOOLS                         - list available tools, as a JSON array
+//	CALL <tool> [json-arguments]  - call a tool, returning its JSON result
+//	COMMAND ...                   - answered with an empty array, so
+//	                                redis-cli's startup introspection
+//	                                doesn't fail against this server
+//	QUIT                          - close the connection
+type RESPTransport struct {
+	handler Handler
+	addr    string
+	ln      net.Listener
+}
+
+// NewRESPTransport creates a RESP transport listening on addr.
+func NewRESPTransport(handler Handler, addr string) *RESPTransport {
+	return &RESPTransport{handler: handler, addr: addr}
+}
+
+// Start listens on addr and serves RESP connections, one goroutine per
+// connection, until ctx is cancelled.
+func (t *RESPTransport) Start(ctx context.Context) error {
+	ln, err := net.Listen("tcp", t.addr)
+	if err != nil {
+		return fmt.Errorf("resp: failed to listen on %s: %w", t.addr, err)
+	}
+	t.ln = ln
+
+	slog.Info("Starting MCP RESP transport", "addr", t.addr)
+
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				slog.Info("Stopping MCP RESP transport")
+				return nil
+			}
+			slog.Error("resp: accept failed", "error", err)
+			continue
+		}
+		go t.handleConn(ctx, conn)
+	}
+}
+
+// Stop closes the listener, ending Start's Accept loop.
+func (t *RESPTransport) Stop() error {
+	if t.ln == nil {
+		return nil
+	}
+	return t.ln.Close()
+}
+
+func (t *RESPTransport) handleConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			if err != io.EOF {
+				slog.Debug("resp: failed to read command", "error", err)
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		if !t.dispatch(ctx, conn, args) {
+			return
+		}
+	}
+}
+
+// dispatch runs one command and writes its RESP response. It returns
+// false when the connection should close (QUIT, or a write failure).
+func (t *RESPTransport) dispatch(ctx context.Context, conn net.Conn, args []string) bool {
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		msg := "PONG"
+		if len(args) > 1 {
+			msg = args[1]
+		}
+		return writeBulkString(conn, msg) == nil
+
+	case "QUIT":
+		writeSimpleString(conn, "OK")
+		return false
+
+	case "COMMAND":
+		return writeArrayHeader(conn, 0) == nil
+
+	case "TOOLS":
+		result, err := t.handler.ListTools(ctx)
+		if err != nil {
+			return writeError(conn, "ERR "+err.Error()) == nil
+		}
+		data, err := json.Marshal(result.Tools)
+		if err != nil {
+			return writeError(conn, "ERR "+err.Error()) == nil
+		}
+		return writeBulkString(conn, string(data)) == nil
+
+	case "CALL":
+		if len(args) < 2 {
+			return writeError(conn, "ERR CALL requires a tool name") == nil
+		}
+
+		var toolArgs map[string]interface{}
+		if len(args) > 2 {
+			if err := json.Unmarshal([]byte(args[2]), &toolArgs); err != nil {
+				return writeError(conn, "ERR invalid JSON arguments: "+err.Error()) == nil
+			}
+		}
+
+		result, err := t.handler.CallTool(ctx, ToolCallParams{Name: args[1], Arguments: toolArgs})
+		if err != nil {
+			return writeError(conn, "ERR "+err.Error()) == nil
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return writeError(conn, "ERR "+err.Error()) == nil
+		}
+		return writeBulkString(conn, string(data)) == nil
+
+	default:
+		return writeError(conn, fmt.Sprintf("ERR unknown command %q", args[0])) == nil
+	}
+}
+
+// maxRESPMultibulkCount and maxRESPBulkLen cap the sizes readCommand will
+// allocate for, so a client (malicious or just malformed) can't force a
+// single oversized allocation by sending a huge multibulk count or bulk
+// string length header before any of the payload it claims to carry.
+const (
+	maxRESPMultibulkCount = 1024
+	maxRESPBulkLen        = 16 * 1024 * 1024 // 16 MiB, well past any real tool call's JSON arguments
+)
+
+// readCommand reads one client request as a slice of string arguments. It
+// accepts both the RESP multibulk wire format real clients (redis-cli,
+// Redis client libraries) send, and a plain space-separated inline
+// command line, for a caller testing over `nc`/`telnet`.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, nil
+	}
+
+	if line[0] != '*' {
+		return strings.Fields(line), nil
+	}
+
+	count, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, fmt.Errorf("resp: invalid multibulk length %q: %w", line[1:], err)
+	}
+	if count < 0 {
+		// A negative count is RESP's null array ("*-1\r\n") - treat it the
+		// same as an empty inline line rather than allocating a negative
+		// capacity, which would panic.
+		return nil, nil
+	}
+	if count > maxRESPMultibulkCount {
+		return nil, fmt.Errorf("resp: multibulk length %d exceeds maximum of %d", count, maxRESPMultibulkCount)
+	}
+
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		header = strings.TrimRight(header, "\r\n")
+		if len(header) == 0 || header[0] != '$' {
+			return nil, fmt.Errorf("resp: expected bulk string header, got %q", header)
+		}
+		size, err := strconv.Atoi(header[1:])
+		if err != nil {
+			return nil, fmt.Errorf("resp: invalid bulk string length %q: %w", header[1:], err)
+		}
+		if size < 0 {
+			// A negative size is RESP's null bulk string ("$-1\r\n") - no
+			// payload and no trailing \r\n follows it, unlike a real value.
+			args = append(args, "")
+			continue
+		}
+		if size > maxRESPBulkLen {
+			return nil, fmt.Errorf("resp: bulk string length %d exceeds maximum of %d", size, maxRESPBulkLen)
+		}
+
+		buf := make([]byte, size+2) // +2 for the trailing \r\n
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:size]))
+	}
+
+	return args, nil
+}
+
+func writeSimpleString(w io.Writer, s string) error {
+	_, err := fmt.Fprintf(w, "+%s\r\n", s)
+	return err
+}
+
+func writeError(w io.Writer, s string) error {
+	_, err := fmt.Fprintf(w, "-%s\r\n", s)
+	return err
+}
+
+func writeBulkString(w io.Writer, s string) error {
+	_, err := fmt.Fprintf(w, "$%d\r\n%s\r\n", len(s), s)
+	return err
+}
+
+func writeArrayHeader(w io.Writer, n int) error {
+	_, err := fmt.Fprintf(w, "*%d\r\n", n)
+	return err
+}
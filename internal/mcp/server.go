@@ -6,9 +6,16 @@ import (
 	"log/slog"
 	"os"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"database-mcp/internal/db"
+	"database-mcp/internal/db/cache"
+	"database-mcp/internal/macro"
+	"database-mcp/internal/mcp/streamformat"
+	"database-mcp/internal/migrate"
+	"database-mcp/internal/safety/classifier"
 )
 
 // Server implements the MCP Handler interface
@@ -16,8 +23,25 @@ type Server struct {
 	serverInfo ServerInfo
 	dbManager  *db.Manager
 	config     *Config
+	cursors    *cursorRegistry
+	macros     *macro.Registry
+	migrations []migrate.Migration
+
+	// streamTokens numbers successive stream_sql calls for their progress
+	// notifications' progressToken.
+	streamTokens atomic.Uint64
 }
 
+// GuardrailMode controls how the run_sql cost/cardinality guardrail reacts
+// to an expensive query.
+type GuardrailMode string
+
+const (
+	GuardrailOff   GuardrailMode = "off"
+	GuardrailWarn  GuardrailMode = "warn"
+	GuardrailBlock GuardrailMode = "block"
+)
+
 // Config holds server configuration
 type Config struct {
 	ReadOnly       bool
@@ -25,7 +49,68 @@ type Config struct {
 	QueryTimeoutMs int
 	PrimaryDBURL   string
 	AnalyticsDBURL string
-	TransportMode  string
+	// DatabaseURLs is the full name -> DSN map passed to db.NewManager,
+	// seeded from PrimaryDBURL/AnalyticsDBURL plus any DB_<NAME>_URL
+	// environment variables for additional named databases.
+	DatabaseURLs  map[string]string
+	TransportMode string
+	// HTTPAddr is the bind address HTTPTransport listens on when
+	// TransportMode is "http".
+	HTTPAddr string
+	// HTTPAuthToken, if non-empty, requires HTTPTransport requests to
+	// carry "Authorization: Bearer <HTTPAuthToken>".
+	HTTPAuthToken string
+	// RESPAddr is the bind address RESPTransport listens on when
+	// TransportMode is "resp".
+	RESPAddr string
+
+	GuardrailMode     GuardrailMode
+	MaxEstimatedRows  uint64
+	MaxEstimatedBytes uint64
+	MaxEstimatedCost  float64
+
+	MaxConcurrentCursors int
+	CursorTTLMs          int
+
+	// AllowedCategories restricts which classifier.Category values run_sql
+	// will execute; a query classified outside this set is rejected before
+	// it reaches the driver. Derived from ReadOnly by default, overridable
+	// with ALLOWED_CATEGORIES.
+	AllowedCategories []classifier.Category
+
+	// CacheMode selects the result-cache backend for run_sql, explain_sql,
+	// and list_schemas ("off", "memory", or "redis").
+	CacheMode cache.Mode
+	// CacheMaxBytes bounds a "memory" cache's total cached value size.
+	CacheMaxBytes int64
+	// CacheMaxEntries bounds a "memory" cache's entry count, in addition to
+	// CacheMaxBytes.
+	CacheMaxEntries int
+	// CacheTTLMs is the default time a cached entry stays fresh before it's
+	// treated as a miss, overridable per MCP call via the "cacheTtlMs" tool
+	// argument.
+	CacheTTLMs int
+	// CacheRedisAddr is the Redis server address used when CacheMode is
+	// "redis".
+	CacheRedisAddr string
+
+	// StreamFormat selects the payload encoding stream_sql uses to return
+	// its fully-drained result ("ndjson" or "arrow").
+	StreamFormat streamformat.Format
+	// StreamProgressRows is how often, in rows, stream_sql emits a
+	// notifications/progress notification while draining a query.
+	StreamProgressRows int
+
+	// MacrosFile, if set, is a YAML file of named, parameterized SQL
+	// queries that are published as additional MCP tools.
+	MacrosFile string
+
+	// MigrationsDir, if set, is a directory of goose-style SQL migration
+	// files published as the run_migrations tool.
+	MigrationsDir string
+	// MigrationsTable is the tracking table run_migrations uses to record
+	// applied versions.
+	MigrationsTable string
 }
 
 // NewServer creates a new MCP server
@@ -35,23 +120,70 @@ func NewServer() (*Server, error) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	dbManager, err := db.NewManager(config.PrimaryDBURL, config.AnalyticsDBURL)
+	dbManager, err := db.NewManager(config.DatabaseURLs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create database manager: %w", err)
 	}
 
+	resultCache, err := cache.New(config.CacheMode, config.CacheMaxBytes, config.CacheMaxEntries, config.CacheRedisAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create result cache: %w", err)
+	}
+	dbManager.SetCache(resultCache, time.Duration(config.CacheTTLMs)*time.Millisecond)
+
+	if _, err := streamformat.New(config.StreamFormat); err != nil {
+		return nil, err
+	}
+
+	macros, err := macro.LoadFile(config.MacrosFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range macros {
+		if m.Name != macro.InitMacroName && builtinToolNames[m.Name] {
+			return nil, fmt.Errorf("macro %q in %s has the same name as a built-in tool", m.Name, config.MacrosFile)
+		}
+	}
+
+	migrations, err := migrate.LoadDir(config.MigrationsDir)
+	if err != nil {
+		return nil, err
+	}
+
 	server := &Server{
 		serverInfo: ServerInfo{
 			Name:    "Database MCP Server",
 			Version: getVersion(),
 		},
-		dbManager: dbManager,
-		config:    config,
+		dbManager:  dbManager,
+		config:     config,
+		cursors:    newCursorRegistry(time.Duration(config.CursorTTLMs)*time.Millisecond, config.MaxConcurrentCursors),
+		macros:     macro.NewRegistry(macros),
+		migrations: migrations,
 	}
 
 	return server, nil
 }
 
+// builtinToolNames are the tool names ListTools can publish (some only
+// when their feature is configured), so a macro can't shadow one of them.
+var builtinToolNames = map[string]bool{
+	"list_schemas":     true,
+	"list_tables":      true,
+	"describe_table":   true,
+	"describe_spatial": true,
+	"run_sql":          true,
+	"explain_sql":      true,
+	"build_select":     true,
+	"summarize_schema": true,
+	"run_sql_stream":   true,
+	"stream_sql":       true,
+	"fetch_next":       true,
+	"run_migrations":   true,
+	"cache_stats":      true,
+	"cache_invalidate": true,
+}
+
 // Start starts the MCP server
 func (s *Server) Start(ctx context.Context) error {
 	slog.Info("Starting MCP server",
@@ -65,14 +197,20 @@ func (s *Server) Start(ctx context.Context) error {
 	}
 	defer s.dbManager.Close()
 
+	s.runInitMacro(ctx)
+
 	// Create transport based on mode
 	var transport Transport
 	switch s.config.TransportMode {
 	case "stdio":
 		transport = NewStdioTransport(s)
 	case "http":
-		// TODO: Implement HTTP transport in Phase 1.5
-		return fmt.Errorf("HTTP transport not yet implemented")
+		if s.config.HTTPAuthToken == "" {
+			slog.Warn("Starting HTTP transport without MCP_AUTH_TOKEN set - every request, including run_sql, will be accepted unauthenticated", "addr", s.config.HTTPAddr)
+		}
+		transport = NewHTTPTransport(s, s.config.HTTPAddr, s.config.HTTPAuthToken)
+	case "resp":
+		transport = NewRESPTransport(s, s.config.RESPAddr)
 	default:
 		return fmt.Errorf("unsupported transport mode: %s", s.config.TransportMode)
 	}
@@ -117,8 +255,8 @@ func (s *Server) ListTools(ctx context.Context) (*ToolListResult, error) {
 				"properties": map[string]interface{}{
 					"database": map[string]interface{}{
 						"type":        "string",
-						"description": "Database to query (primary or analytics)",
-						"enum":        []string{"primary", "analytics"},
+						"description": "Database to query",
+						"enum":        s.dbManager.DatabaseNames(),
 						"default":     "primary",
 					},
 				},
@@ -132,8 +270,8 @@ func (s *Server) ListTools(ctx context.Context) (*ToolListResult, error) {
 				"properties": map[string]interface{}{
 					"database": map[string]interface{}{
 						"type":        "string",
-						"description": "Database to query (primary or analytics)",
-						"enum":        []string{"primary", "analytics"},
+						"description": "Database to query",
+						"enum":        s.dbManager.DatabaseNames(),
 						"default":     "primary",
 					},
 					"schema": map[string]interface{}{
@@ -152,8 +290,8 @@ func (s *Server) ListTools(ctx context.Context) (*ToolListResult, error) {
 				"properties": map[string]interface{}{
 					"database": map[string]interface{}{
 						"type":        "string",
-						"description": "Database to query (primary or analytics)",
-						"enum":        []string{"primary", "analytics"},
+						"description": "Database to query",
+						"enum":        s.dbManager.DatabaseNames(),
 						"default":     "primary",
 					},
 					"schema": map[string]interface{}{
@@ -168,6 +306,65 @@ func (s *Server) ListTools(ctx context.Context) (*ToolListResult, error) {
 				"required": []string{"schema", "table"},
 			},
 		},
+		{
+			Name:        "describe_spatial",
+			Description: "Get PostGIS metadata (SRID, geometry subtype, GiST index coverage) for a table's geometry/geography columns. PostgreSQL/PostGIS only",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"database": map[string]interface{}{
+						"type":        "string",
+						"description": "Database to query",
+						"enum":        s.dbManager.DatabaseNames(),
+						"default":     "primary",
+					},
+					"schema": map[string]interface{}{
+						"type":        "string",
+						"description": "Schema name",
+					},
+					"table": map[string]interface{}{
+						"type":        "string",
+						"description": "Table name",
+					},
+				},
+				"required": []string{"schema", "table"},
+			},
+		},
+		{
+			Name:        "summarize_schema",
+			Description: "Summarize a schema's tables (structure plus sample rows) from a single consistent point-in-time read-only snapshot",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"database": map[string]interface{}{
+						"type":        "string",
+						"description": "Database to query",
+						"enum":        s.dbManager.DatabaseNames(),
+						"default":     "primary",
+					},
+					"schema": map[string]interface{}{
+						"type":        "string",
+						"description": "Schema name",
+					},
+					"tables": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Tables to summarize; defaults to the first maxTables tables in the schema",
+					},
+					"maxTables": map[string]interface{}{
+						"type":        "integer",
+						"description": "Cap on how many tables to summarize when tables isn't given",
+						"default":     5,
+					},
+					"sampleRows": map[string]interface{}{
+						"type":        "integer",
+						"description": "Rows to sample per table",
+						"default":     10,
+					},
+				},
+				"required": []string{"schema"},
+			},
+		},
 		{
 			Name:        "run_sql",
 			Description: "Execute SQL queries with safety checks",
@@ -176,14 +373,21 @@ func (s *Server) ListTools(ctx context.Context) (*ToolListResult, error) {
 				"properties": map[string]interface{}{
 					"database": map[string]interface{}{
 						"type":        "string",
-						"description": "Database to query (primary or analytics)",
-						"enum":        []string{"primary", "analytics"},
+						"description": "Database to query",
+						"enum":        s.dbManager.DatabaseNames(),
 						"default":     "primary",
 					},
 					"query": map[string]interface{}{
 						"type":        "string",
 						"description": "SQL query to execute",
 					},
+					"parameters": map[string]interface{}{
+						"description": "Bind values for the query. Either an array matched positionally against `?` placeholders, or an object matched against `:name` placeholders",
+						"oneOf": []map[string]interface{}{
+							{"type": "array"},
+							{"type": "object"},
+						},
+					},
 					"limit": map[string]interface{}{
 						"type":        "integer",
 						"description": "Maximum number of rows to return",
@@ -191,6 +395,16 @@ func (s *Server) ListTools(ctx context.Context) (*ToolListResult, error) {
 						"maximum":     s.config.MaxRows,
 						"default":     1000,
 					},
+					"cache": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Whether this query may be served from or populate the result cache",
+						"default":     true,
+					},
+					"cacheTtlMs": map[string]interface{}{
+						"type":        "integer",
+						"description": "Override the server's default cache TTL (in milliseconds) for the result this call writes",
+						"minimum":     0,
+					},
 				},
 				"required": []string{"query"},
 			},
@@ -203,18 +417,240 @@ func (s *Server) ListTools(ctx context.Context) (*ToolListResult, error) {
 				"properties": map[string]interface{}{
 					"database": map[string]interface{}{
 						"type":        "string",
-						"description": "Database to query (primary or analytics)",
-						"enum":        []string{"primary", "analytics"},
+						"description": "Database to query",
+						"enum":        s.dbManager.DatabaseNames(),
 						"default":     "primary",
 					},
 					"query": map[string]interface{}{
 						"type":        "string",
 						"description": "SQL query to explain",
 					},
+					"cache": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Whether this plan may be served from or populate the result cache",
+						"default":     true,
+					},
+					"cacheTtlMs": map[string]interface{}{
+						"type":        "integer",
+						"description": "Override the server's default cache TTL (in milliseconds) for the result this call writes",
+						"minimum":     0,
+					},
 				},
 				"required": []string{"query"},
 			},
 		},
+		{
+			Name:        "build_select",
+			Description: "Build (and optionally execute) a SELECT query from a structured description instead of raw SQL. Every identifier is validated against the live schema via describe_table before being used",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"database": map[string]interface{}{
+						"type":        "string",
+						"description": "Database to query",
+						"enum":        s.dbManager.DatabaseNames(),
+						"default":     "primary",
+					},
+					"schema": map[string]interface{}{
+						"type":        "string",
+						"description": "Schema name",
+					},
+					"table": map[string]interface{}{
+						"type":        "string",
+						"description": "Table name",
+					},
+					"columns": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Columns to select; omit or empty for all columns",
+					},
+					"where": map[string]interface{}{
+						"type":        "array",
+						"description": "Conditions ANDed together",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"col":   map[string]interface{}{"type": "string"},
+								"op":    map[string]interface{}{"type": "string", "enum": []string{"eq", "neq", "in", "notIn", "between", "like", "isNull", "isNotNull"}},
+								"value": map[string]interface{}{"description": "Comparison value; an array for in/notIn/between"},
+							},
+							"required": []string{"col", "op"},
+						},
+					},
+					"groupBy": map[string]interface{}{
+						"type":  "array",
+						"items": map[string]interface{}{"type": "string"},
+					},
+					"orderBy": map[string]interface{}{
+						"type":  "array",
+						"items": map[string]interface{}{"type": "string", "description": "Column name, optionally suffixed with \" desc\""},
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of rows to return",
+						"minimum":     1,
+						"maximum":     s.config.MaxRows,
+						"default":     1000,
+					},
+					"execute": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Whether to run the built query and return rows, or just return the generated SQL",
+						"default":     true,
+					},
+				},
+				"required": []string{"schema", "table"},
+			},
+		},
+		{
+			Name:        "run_sql_stream",
+			Description: "Execute a SQL query and return the first batch of rows plus a cursor for fetching the rest via fetch_next, instead of buffering the whole result set",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"database": map[string]interface{}{
+						"type":        "string",
+						"description": "Database to query",
+						"enum":        s.dbManager.DatabaseNames(),
+						"default":     "primary",
+					},
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "SQL query to execute",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum total rows across all batches",
+						"minimum":     1,
+						"maximum":     s.config.MaxRows,
+						"default":     1000,
+					},
+					"batchSize": map[string]interface{}{
+						"type":        "integer",
+						"description": "Rows to return per batch",
+						"minimum":     1,
+						"default":     100,
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
+		{
+			Name:        "stream_sql",
+			Description: fmt.Sprintf("Execute a SQL query, draining the full (row-capped) result set and returning it encoded as %s in a single response, with progress notifications emitted every %d rows while it drains", s.config.StreamFormat, s.config.StreamProgressRows),
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"database": map[string]interface{}{
+						"type":        "string",
+						"description": "Database to query",
+						"enum":        s.dbManager.DatabaseNames(),
+						"default":     "primary",
+					},
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "SQL query to execute",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum total rows to stream, enforced as a LIMIT on the query itself so a rogue query can't exhaust memory",
+						"minimum":     1,
+						"maximum":     s.config.MaxRows,
+						"default":     1000,
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
+		{
+			Name:        "fetch_next",
+			Description: "Fetch the next batch of rows for a cursor returned by run_sql_stream",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"cursor": map[string]interface{}{
+						"type":        "string",
+						"description": "Cursor token returned by run_sql_stream or a previous fetch_next call",
+					},
+					"batchSize": map[string]interface{}{
+						"type":        "integer",
+						"description": "Rows to return in this batch",
+						"minimum":     1,
+						"default":     100,
+					},
+				},
+				"required": []string{"cursor"},
+			},
+		},
+	}
+
+	for _, m := range s.macros.All() {
+		tools = append(tools, Tool{
+			Name:        m.Name,
+			Description: m.Description,
+			InputSchema: m.InputSchema(),
+		})
+	}
+
+	if s.config.MigrationsDir != "" {
+		tools = append(tools, Tool{
+			Name:        "run_migrations",
+			Description: "Inspect or apply goose-style SQL migration files from MIGRATIONS_DIR against a database",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"database": map[string]interface{}{
+						"type":        "string",
+						"description": "Database to migrate",
+						"enum":        s.dbManager.DatabaseNames(),
+						"default":     "primary",
+					},
+					"action": map[string]interface{}{
+						"type":        "string",
+						"description": "status: report each migration's applied state. up: apply pending migrations. down: revert the most recently applied ones.",
+						"enum":        []string{"status", "up", "down"},
+						"default":     "status",
+					},
+					"steps": map[string]interface{}{
+						"type":        "integer",
+						"description": "For up, how many pending migrations to apply (0 = all). For down, how many to revert (0 defaults to 1).",
+						"minimum":     0,
+						"default":     0,
+					},
+				},
+			},
+		})
+	}
+
+	if s.config.CacheMode != cache.ModeOff && s.config.CacheMode != "" {
+		tools = append(tools,
+			Tool{
+				Name:        "cache_stats",
+				Description: "Report result cache hit/miss/eviction counters and current size",
+				InputSchema: map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{},
+				},
+			},
+			Tool{
+				Name:        "cache_invalidate",
+				Description: "Evict cached results for a database, or for a single table within it",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"database": map[string]interface{}{
+							"type":        "string",
+							"description": "Database whose cached results to evict",
+							"enum":        s.dbManager.DatabaseNames(),
+							"default":     "primary",
+						},
+						"table": map[string]interface{}{
+							"type":        "string",
+							"description": "Table to scope invalidation to (omit to evict every cached result for the database)",
+						},
+					},
+				},
+			},
+		)
 	}
 
 	return &ToolListResult{Tools: tools}, nil
@@ -235,11 +671,32 @@ func (s *Server) CallTool(ctx context.Context, params ToolCallParams) (*ToolCall
 		return s.handleListTables(queryCtx, params.Arguments)
 	case "describe_table":
 		return s.handleDescribeTable(queryCtx, params.Arguments)
+	case "describe_spatial":
+		return s.handleDescribeSpatial(queryCtx, params.Arguments)
+	case "summarize_schema":
+		return s.handleSummarizeSchema(queryCtx, params.Arguments)
 	case "run_sql":
 		return s.handleRunSQL(queryCtx, params.Arguments)
 	case "explain_sql":
 		return s.handleExplainSQL(queryCtx, params.Arguments)
+	case "build_select":
+		return s.handleBuildSelect(queryCtx, params.Arguments)
+	case "run_sql_stream":
+		return s.handleRunSQLStream(queryCtx, params.Arguments)
+	case "stream_sql":
+		return s.handleStreamSQL(queryCtx, params.Arguments)
+	case "fetch_next":
+		return s.handleFetchNext(queryCtx, params.Arguments)
+	case "run_migrations":
+		return s.handleRunMigrations(queryCtx, params.Arguments)
+	case "cache_stats":
+		return s.handleCacheStats(queryCtx, params.Arguments)
+	case "cache_invalidate":
+		return s.handleCacheInvalidate(queryCtx, params.Arguments)
 	default:
+		if m, ok := s.macros.Get(params.Name); ok {
+			return s.handleMacro(queryCtx, m, params.Arguments)
+		}
 		return &ToolCallResult{
 			Content: []ContentItem{
 				{Type: "text", Text: fmt.Sprintf("Unknown tool: %s", params.Name)},
@@ -285,6 +742,31 @@ func loadConfig() (*Config, error) {
 		PrimaryDBURL:   os.Getenv("DB_PRIMARY_URL"),
 		AnalyticsDBURL: os.Getenv("DB_ANALYTICS_URL"),
 		TransportMode:  getEnvString("MCP_MODE", "stdio"),
+		HTTPAddr:       getEnvString("MCP_HTTP_ADDR", "127.0.0.1:8080"),
+		HTTPAuthToken:  os.Getenv("MCP_AUTH_TOKEN"),
+		RESPAddr:       getEnvString("MCP_RESP_ADDR", "127.0.0.1:6380"),
+
+		GuardrailMode:     GuardrailMode(getEnvString("GUARDRAIL_MODE", string(GuardrailWarn))),
+		MaxEstimatedRows:  uint64(getEnvInt("MAX_ESTIMATED_ROWS", 10_000_000)),
+		MaxEstimatedBytes: uint64(getEnvInt("MAX_ESTIMATED_BYTES", 1<<30)),
+		MaxEstimatedCost:  float64(getEnvInt("MAX_ESTIMATED_COST", 0)),
+
+		MaxConcurrentCursors: getEnvInt("MAX_CONCURRENT_CURSORS", 50),
+		CursorTTLMs:          getEnvInt("CURSOR_TTL_MS", 5*60*1000),
+
+		CacheMode:       cache.Mode(getEnvString("CACHE_MODE", string(cache.ModeOff))),
+		CacheMaxBytes:   int64(getEnvInt("CACHE_MAX_BYTES", 64<<20)),
+		CacheMaxEntries: getEnvInt("CACHE_MAX_ENTRIES", 10_000),
+		CacheTTLMs:      getEnvInt("CACHE_TTL_MS", 30_000),
+		CacheRedisAddr:  getEnvString("CACHE_REDIS_ADDR", ""),
+
+		StreamFormat:       streamformat.Format(getEnvString("STREAM_FORMAT", string(streamformat.FormatNDJSON))),
+		StreamProgressRows: getEnvInt("STREAM_PROGRESS_ROWS", 1000),
+
+		MacrosFile: getEnvString("MACROS_FILE", ""),
+
+		MigrationsDir:   getEnvString("MIGRATIONS_DIR", ""),
+		MigrationsTable: getEnvString("MIGRATIONS_TABLE", ""),
 	}
 
 	// Validate required config
@@ -292,9 +774,60 @@ func loadConfig() (*Config, error) {
 		return nil, fmt.Errorf("DB_PRIMARY_URL is required")
 	}
 
+	config.DatabaseURLs = map[string]string{"primary": config.PrimaryDBURL}
+	if config.AnalyticsDBURL != "" {
+		config.DatabaseURLs["analytics"] = config.AnalyticsDBURL
+	}
+	for name, dsn := range extraDatabaseURLs() {
+		config.DatabaseURLs[name] = dsn
+	}
+
+	config.AllowedCategories = allowedCategories(config.ReadOnly)
+
 	return config, nil
 }
 
+// allowedCategories derives the set of classifier.Category values run_sql
+// is permitted to execute. ALLOWED_CATEGORIES (comma-separated) overrides
+// the ReadOnly-derived default entirely, for operators who want something
+// between "reads only" and "anything goes" (e.g. "read,write" to allow
+// writes but still refuse DDL).
+func allowedCategories(readOnly bool) []classifier.Category {
+	if raw := os.Getenv("ALLOWED_CATEGORIES"); raw != "" {
+		var cats []classifier.Category
+		for _, part := range strings.Split(raw, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				cats = append(cats, classifier.Category(part))
+			}
+		}
+		return cats
+	}
+	if readOnly {
+		return []classifier.Category{classifier.CategoryRead}
+	}
+	return []classifier.Category{classifier.CategoryRead, classifier.CategoryWrite, classifier.CategoryDDL}
+}
+
+// extraDatabaseURLs scans the environment for DB_<NAME>_URL variables
+// beyond DB_PRIMARY_URL/DB_ANALYTICS_URL, so operators can register an
+// arbitrary number of additional named databases without a config format
+// change, e.g. DB_REPORTING_URL=... registers a database named "reporting".
+func extraDatabaseURLs() map[string]string {
+	urls := make(map[string]string)
+	for _, env := range os.Environ() {
+		key, value, ok := strings.Cut(env, "=")
+		if !ok || !strings.HasPrefix(key, "DB_") || !strings.HasSuffix(key, "_URL") {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(key, "DB_"), "_URL"))
+		if name == "primary" || name == "analytics" || value == "" {
+			continue
+		}
+		urls[name] = value
+	}
+	return urls
+}
+
 // Helper functions for environment variables
 func getEnvString(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
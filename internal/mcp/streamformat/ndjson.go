@@ -0,0 +1,45 @@
+package streamformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ndjsonEncoder writes one JSON object per row, newline-delimited, so a
+// client can parse the payload incrementally instead of waiting for a
+// closing "]". This is the default STREAM_FORMAT.
+type ndjsonEncoder struct {
+	buf     bytes.Buffer
+	columns []string
+}
+
+func newNDJSONEncoder() *ndjsonEncoder {
+	return &ndjsonEncoder{}
+}
+
+func (e *ndjsonEncoder) Columns(columns []string) error {
+	e.columns = columns
+	return nil
+}
+
+func (e *ndjsonEncoder) Row(values []interface{}) error {
+	if len(values) != len(e.columns) {
+		return fmt.Errorf("ndjson: row has %d values, expected %d columns", len(values), len(e.columns))
+	}
+	row := make(map[string]interface{}, len(e.columns))
+	for i, col := range e.columns {
+		row[col] = values[i]
+	}
+	line, err := json.Marshal(row)
+	if err != nil {
+		return fmt.Errorf("ndjson: failed to marshal row: %w", err)
+	}
+	e.buf.Write(line)
+	e.buf.WriteByte('\n')
+	return nil
+}
+
+func (e *ndjsonEncoder) Finish() ([]byte, string, error) {
+	return e.buf.Bytes(), "application/x-ndjson", nil
+}
@@ -0,0 +1,44 @@
+// Package streamformat encodes the rows pulled off a db.RowStream for the
+// stream_sql MCP tool, so an analytics client paging millions of rows out
+// of ClickHouse/Postgres gets them as NDJSON or Arrow IPC instead of one
+// giant buffered JSON array. The format is chosen once at server startup
+// via STREAM_FORMAT and shared by every stream_sql call.
+package streamformat
+
+import "fmt"
+
+// Format selects an Encoder implementation.
+type Format string
+
+const (
+	FormatNDJSON Format = "ndjson"
+	FormatArrow  Format = "arrow"
+)
+
+// Encoder turns a stream of rows into an on-the-wire payload, one row at a
+// time, so the caller never has to buffer the full result set to encode
+// it. Columns is called once before any Row calls.
+type Encoder interface {
+	// Columns records the result's column names. Called once, before the
+	// first Row call.
+	Columns(columns []string) error
+
+	// Row encodes a single row's values.
+	Row(values []interface{}) error
+
+	// Finish flushes any trailer/footer the format needs and returns the
+	// complete encoded payload plus its MIME type.
+	Finish() ([]byte, string, error)
+}
+
+// New constructs the Encoder for format.
+func New(format Format) (Encoder, error) {
+	switch format {
+	case FormatNDJSON, "":
+		return newNDJSONEncoder(), nil
+	case FormatArrow:
+		return newArrowEncoder(), nil
+	default:
+		return nil, fmt.Errorf("streamformat: unknown STREAM_FORMAT %q", format)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"sync"
 )
 
 // Transport interface for different MCP transport methods
@@ -19,18 +20,22 @@ type Transport interface {
 
 // StdioTransport implements MCP over stdio (standard input/output)
 type StdioTransport struct {
-	handler Handler
-	reader  *bufio.Reader
-	writer  io.Writer
+	handler  Handler
+	reader   *bufio.Reader
+	writer   io.Writer
+	writeMu  sync.Mutex
+	notifier Notifier
 }
 
 // NewStdioTransport creates a new stdio transport
 func NewStdioTransport(handler Handler) *StdioTransport {
-	return &StdioTransport{
+	t := &StdioTransport{
 		handler: handler,
 		reader:  bufio.NewReader(os.Stdin),
 		writer:  os.Stdout,
 	}
+	t.notifier = &writerNotifier{mu: &t.writeMu, writer: t.writer}
+	return t
 }
 
 // Start begins processing MCP requests over stdio
@@ -105,13 +110,18 @@ func (t *StdioTransport) processRequest(ctx context.Context) error {
 		return t.sendResponse(response)
 	}
 
-	// Process the request
-	response := t.handleRequest(ctx, req)
+	// Process the request, with a Notifier attached so a long-running
+	// handler (e.g. stream_sql) can emit progress notifications before
+	// its response is ready.
+	response := dispatchRequest(WithNotifier(ctx, t.notifier), t.handler, req)
 	return t.sendResponse(response)
 }
 
-// handleRequest processes an MCP request and returns a response
-func (t *StdioTransport) handleRequest(ctx context.Context, req MCPRequest) MCPResponse {
+// dispatchRequest processes a single MCP JSON-RPC request against handler
+// and returns its response, shared by StdioTransport and HTTPTransport so
+// their JSON-RPC method routing lives in one place. RESPTransport has its
+// own command set and calls handler directly.
+func dispatchRequest(ctx context.Context, handler Handler, req MCPRequest) MCPResponse {
 	response := MCPResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
@@ -131,7 +141,7 @@ func (t *StdioTransport) handleRequest(ctx context.Context, req MCPRequest) MCPR
 			return response
 		}
 
-		result, err := t.handler.Initialize(ctx, params)
+		result, err := handler.Initialize(ctx, params)
 		if err != nil {
 			response.Error = &MCPError{
 				Code:    ErrorCodeInternalError,
@@ -143,7 +153,7 @@ func (t *StdioTransport) handleRequest(ctx context.Context, req MCPRequest) MCPR
 		}
 
 	case MethodListTools:
-		result, err := t.handler.ListTools(ctx)
+		result, err := handler.ListTools(ctx)
 		if err != nil {
 			response.Error = &MCPError{
 				Code:    ErrorCodeInternalError,
@@ -165,7 +175,7 @@ func (t *StdioTransport) handleRequest(ctx context.Context, req MCPRequest) MCPR
 			return response
 		}
 
-		result, err := t.handler.CallTool(ctx, params)
+		result, err := handler.CallTool(ctx, params)
 		if err != nil {
 			response.Error = &MCPError{
 				Code:    ErrorCodeInternalError,
@@ -196,8 +206,12 @@ func (t *StdioTransport) sendResponse(response MCPResponse) error {
 
 	slog.Debug("Sending response", "raw", string(data))
 
-	// Write response followed by newline
+	// Write response followed by newline. Guarded by the same mutex as
+	// writerNotifier so a stream_sql call's progress notifications can't
+	// interleave mid-line with its own final response.
+	t.writeMu.Lock()
 	_, err = fmt.Fprintf(t.writer, "%s\n", data)
+	t.writeMu.Unlock()
 	if err != nil {
 		slog.Error("Failed to write response", "error", err)
 		return err
@@ -49,6 +49,16 @@ const (
 	MethodDescribeTable = "describe_table"
 	MethodRunSQL        = "run_sql"
 	MethodExplainSQL    = "explain_sql"
+	MethodBuildSelect   = "build_select"
+	MethodRunSQLStream  = "run_sql_stream"
+	MethodFetchNext     = "fetch_next"
+	MethodStreamSQL     = "stream_sql"
+
+	// MethodProgressNotification is the MCP notification method stream_sql
+	// sends every Config.StreamProgressRows rows while it drains a query, per
+	// the MCP spec's progress-notification convention. It carries no id
+	// and expects no response.
+	MethodProgressNotification = "notifications/progress"
 )
 
 // InitializeParams represents the parameters for the initialize method
@@ -126,6 +136,30 @@ type ToolCallResult struct {
 type ContentItem struct {
 	Type string `json:"type"`
 	Text string `json:"text,omitempty"`
+
+	// Data and MimeType carry a non-text payload (e.g. an NDJSON or Arrow
+	// IPC stream encoded by streamformat), base64-encoded as Data since
+	// MCP content travels over a JSON-RPC transport. Only set when Type
+	// is "data".
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// ProgressParams is the payload of a notifications/progress notification,
+// sent unsolicited (no id, no response expected) while a long-running tool
+// call like stream_sql is still in flight.
+type ProgressParams struct {
+	ProgressToken string `json:"progressToken"`
+	Progress      int    `json:"progress"`
+	Total         int    `json:"total,omitempty"`
+}
+
+// MCPNotification represents an MCP JSON-RPC notification - a request with
+// no id, for which no response is sent.
+type MCPNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
 }
 
 // Note: Database-specific types moved to internal/types package to avoid import cycles
@@ -0,0 +1,164 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var filenamePattern = regexp.MustCompile(`^(\d+)_(.+)\.sql$`)
+
+const (
+	gooseUp             = "-- +goose Up"
+	gooseDown           = "-- +goose Down"
+	gooseStatementBegin = "-- +goose StatementBegin"
+	gooseStatementEnd   = "-- +goose StatementEnd"
+)
+
+// LoadDir parses every *.sql file in dir as a goose-style migration,
+// returning them sorted by version. An empty dir returns no migrations and
+// no error, so MIGRATIONS_DIR can be left unset.
+func LoadDir(dir string) ([]Migration, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: failed to read migrations dir %s: %w", dir, err)
+	}
+
+	seen := make(map[int64]string, len(entries))
+	migrations := make([]Migration, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		m, err := parseFile(dir, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := seen[m.Version]; ok {
+			return nil, fmt.Errorf("migrate: %s and %s both declare version %d", prev, m.Filename, m.Version)
+		}
+		seen[m.Version] = m.Filename
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func parseFile(dir, filename string) (Migration, error) {
+	match := filenamePattern.FindStringSubmatch(filename)
+	if match == nil {
+		return Migration{}, fmt.Errorf("migrate: %s does not match the NNN_name.sql naming convention", filename)
+	}
+	version, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return Migration{}, fmt.Errorf("migrate: %s has an invalid version: %w", filename, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, filename))
+	if err != nil {
+		return Migration{}, fmt.Errorf("migrate: failed to read %s: %w", filename, err)
+	}
+
+	up, down, err := parseSections(string(data))
+	if err != nil {
+		return Migration{}, fmt.Errorf("migrate: %s: %w", filename, err)
+	}
+	if len(up) == 0 {
+		return Migration{}, fmt.Errorf("migrate: %s has no %q section", filename, gooseUp)
+	}
+
+	return Migration{
+		Version:        version,
+		Name:           match[2],
+		Filename:       filename,
+		UpStatements:   up,
+		DownStatements: down,
+	}, nil
+}
+
+// parseSections splits content's "-- +goose Up" and "-- +goose Down"
+// blocks into individual statements.
+func parseSections(content string) (up, down []string, err error) {
+	var upText, downText strings.Builder
+	current := &strings.Builder{}
+
+	for _, line := range strings.Split(content, "\n") {
+		switch strings.TrimSpace(line) {
+		case gooseUp:
+			current = &upText
+			continue
+		case gooseDown:
+			current = &downText
+			continue
+		}
+		current.WriteString(line)
+		current.WriteByte('\n')
+	}
+
+	up, err = splitStatements(upText.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	down, err = splitStatements(downText.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	return up, down, nil
+}
+
+// splitStatements breaks a goose section into individual statements,
+// splitting on ";" except inside a "-- +goose StatementBegin" /
+// "-- +goose StatementEnd" block, which is kept as one statement
+// regardless of internal semicolons (e.g. a trigger or function body).
+func splitStatements(section string) ([]string, error) {
+	var statements []string
+	rest := section
+	for {
+		begin := strings.Index(rest, gooseStatementBegin)
+		if begin < 0 {
+			break
+		}
+		end := strings.Index(rest[begin:], gooseStatementEnd)
+		if end < 0 {
+			return nil, fmt.Errorf("%s without a matching %s", gooseStatementBegin, gooseStatementEnd)
+		}
+
+		statements = append(statements, splitOnSemicolon(rest[:begin])...)
+		block := strings.TrimSpace(rest[begin+len(gooseStatementBegin) : begin+end])
+		if block != "" {
+			statements = append(statements, block)
+		}
+		rest = rest[begin+end+len(gooseStatementEnd):]
+	}
+	statements = append(statements, splitOnSemicolon(rest)...)
+	return statements, nil
+}
+
+// splitOnSemicolon splits text on ";", dropping full-line "--" comments and
+// any resulting empty statements.
+func splitOnSemicolon(text string) []string {
+	var out []string
+	for _, part := range strings.Split(text, ";") {
+		var kept []string
+		for _, line := range strings.Split(part, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "--") {
+				continue
+			}
+			kept = append(kept, line)
+		}
+		if stmt := strings.TrimSpace(strings.Join(kept, "\n")); stmt != "" {
+			out = append(out, stmt)
+		}
+	}
+	return out
+}
@@ -0,0 +1,34 @@
+// Package migrate loads goose-style SQL migration files from a directory
+// and applies them against a configured database, recording applied
+// versions in a schema_migrations table so re-running Up only executes
+// what's still pending.
+package migrate
+
+import "time"
+
+// Migration is one parsed migration file: a numbered version, its
+// human-readable name, and the statements extracted from its
+// "-- +goose Up" / "-- +goose Down" sections.
+type Migration struct {
+	Version        int64
+	Name           string
+	Filename       string
+	UpStatements   []string
+	DownStatements []string
+}
+
+// Status is one migration's applied state, as reported by Runner.Status.
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+// Result is the outcome of applying or reverting one migration via
+// Runner.Up or Runner.Down.
+type Result struct {
+	Version   int64  `json:"version"`
+	Name      string `json:"name"`
+	Direction string `json:"direction"` // "up" or "down"
+}
@@ -0,0 +1,223 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"time"
+
+	"database-mcp/internal/db"
+)
+
+// DefaultTable is the schema_migrations table name a Runner uses when it
+// isn't configured with one explicitly.
+const DefaultTable = "schema_migrations"
+
+// Runner applies a set of parsed Migrations against one named database,
+// tracking which versions have already run in a tracking table.
+type Runner struct {
+	manager  *db.Manager
+	database string
+	table    string
+}
+
+// NewRunner creates a Runner targeting database (a name known to manager),
+// recording applied versions in table. An empty table defaults to
+// DefaultTable.
+func NewRunner(manager *db.Manager, database, table string) *Runner {
+	if table == "" {
+		table = DefaultTable
+	}
+	return &Runner{manager: manager, database: database, table: table}
+}
+
+// ensureTable creates the tracking table if it doesn't already exist, with
+// DDL appropriate for the target database's driver type.
+func (r *Runner) ensureTable(ctx context.Context) error {
+	driver, err := r.manager.GetDriver(r.database)
+	if err != nil {
+		return err
+	}
+	_, err = r.manager.RunSQL(ctx, r.database, createTableSQL(driver.GetType(), r.table), nil, 0)
+	return err
+}
+
+func createTableSQL(dbType db.DatabaseType, table string) string {
+	switch dbType {
+	case db.DatabaseTypeMSSQL:
+		return fmt.Sprintf(`IF NOT EXISTS (SELECT 1 FROM sys.tables WHERE name = '%s') CREATE TABLE %s (version BIGINT PRIMARY KEY, name NVARCHAR(255) NOT NULL, applied_at DATETIME2 NOT NULL)`, table, table)
+	case db.DatabaseTypeClickHouse:
+		return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (version Int64, name String, applied_at DateTime) ENGINE = MergeTree ORDER BY version`, table)
+	default:
+		return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (version BIGINT PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL)`, table)
+	}
+}
+
+// lockKey derives a stable 32-bit key from database and table, scoping the
+// advisory lock Up and Down take to this Runner's tracking table rather
+// than the whole database.
+func (r *Runner) lockKey() int64 {
+	return int64(crc32.ChecksumIEEE([]byte(r.database + ":" + r.table)))
+}
+
+// appliedVersions returns every migration version already recorded in the
+// tracking table, keyed to the time it was applied.
+func (r *Runner) appliedVersions(ctx context.Context) (map[int64]time.Time, error) {
+	result, err := r.manager.RunSQL(ctx, r.database, fmt.Sprintf("SELECT version, applied_at FROM %s ORDER BY version", r.table), nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]time.Time, len(result.Rows))
+	for _, row := range result.Rows {
+		version, ok := asInt64(row[0])
+		if !ok {
+			continue
+		}
+		appliedAt, _ := row[1].(time.Time)
+		applied[version] = appliedAt
+	}
+	return applied, nil
+}
+
+func asInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int32:
+		return int64(n), true
+	case int:
+		return int64(n), true
+	case float64:
+		return int64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// Status reports every migration's applied state, in the order migrations
+// was given (normally ascending version order, as returned by LoadDir).
+func (r *Runner) Status(ctx context.Context, migrations []Migration) ([]Status, error) {
+	if err := r.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(migrations))
+	for _, m := range migrations {
+		st := Status{Version: m.Version, Name: m.Name}
+		if at, ok := applied[m.Version]; ok {
+			st.Applied = true
+			appliedAt := at
+			st.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+// Up applies pending migrations in ascending version order, stopping after
+// steps of them have been applied (0 means apply everything pending). It
+// holds an advisory lock (see Manager.WithAdvisoryLock) on a single pinned
+// connection for its whole duration so a concurrent Up/Down against the
+// same database can't interleave.
+func (r *Runner) Up(ctx context.Context, migrations []Migration, steps int) ([]Result, error) {
+	var results []Result
+	err := r.manager.WithAdvisoryLock(ctx, r.database, r.lockKey(), func(ctx context.Context) error {
+		if err := r.ensureTable(ctx); err != nil {
+			return err
+		}
+		applied, err := r.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if steps > 0 && len(results) >= steps {
+				break
+			}
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+
+			for _, stmt := range m.UpStatements {
+				if _, err := r.manager.RunSQL(ctx, r.database, stmt, nil, 0); err != nil {
+					return fmt.Errorf("migrate: %s: up failed: %w", m.Filename, err)
+				}
+			}
+
+			params := map[string]interface{}{"version": m.Version, "name": m.Name, "appliedAt": time.Now().UTC()}
+			recordSQL := fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (:version, :name, :appliedAt)", r.table)
+			if _, err := r.manager.RunSQL(ctx, r.database, recordSQL, params, 0); err != nil {
+				return fmt.Errorf("migrate: %s: failed to record applied version: %w", m.Filename, err)
+			}
+
+			results = append(results, Result{Version: m.Version, Name: m.Name, Direction: "up"})
+		}
+		return nil
+	})
+	return results, err
+}
+
+// Down reverts the most recently applied migrations in descending version
+// order, stopping after steps of them have been reverted (0 defaults to 1,
+// matching goose's own "down" behavior of reverting a single migration).
+// It holds an advisory lock (see Manager.WithAdvisoryLock) on a single
+// pinned connection for its whole duration so a concurrent Up/Down
+// against the same database can't interleave.
+func (r *Runner) Down(ctx context.Context, migrations []Migration, steps int) ([]Result, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	var results []Result
+	err := r.manager.WithAdvisoryLock(ctx, r.database, r.lockKey(), func(ctx context.Context) error {
+		if err := r.ensureTable(ctx); err != nil {
+			return err
+		}
+		applied, err := r.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+
+		byVersion := make(map[int64]Migration, len(migrations))
+		for _, m := range migrations {
+			byVersion[m.Version] = m
+		}
+
+		appliedVersions := make([]int64, 0, len(applied))
+		for v := range applied {
+			appliedVersions = append(appliedVersions, v)
+		}
+		sort.Slice(appliedVersions, func(i, j int) bool { return appliedVersions[i] > appliedVersions[j] })
+
+		for _, v := range appliedVersions {
+			if len(results) >= steps {
+				break
+			}
+			m, ok := byVersion[v]
+			if !ok {
+				return fmt.Errorf("migrate: applied version %d has no matching loaded migration file", v)
+			}
+
+			for _, stmt := range m.DownStatements {
+				if _, err := r.manager.RunSQL(ctx, r.database, stmt, nil, 0); err != nil {
+					return fmt.Errorf("migrate: %s: down failed: %w", m.Filename, err)
+				}
+			}
+
+			deleteSQL := fmt.Sprintf("DELETE FROM %s WHERE version = :version", r.table)
+			if _, err := r.manager.RunSQL(ctx, r.database, deleteSQL, map[string]interface{}{"version": v}, 0); err != nil {
+				return fmt.Errorf("migrate: %s: failed to clear applied record: %w", m.Filename, err)
+			}
+
+			results = append(results, Result{Version: m.Version, Name: m.Name, Direction: "down"})
+		}
+		return nil
+	})
+	return results, err
+}
@@ -0,0 +1,229 @@
+// Package classifier performs lightweight SQL statement classification so
+// the server can enforce read-only mode and reject multi-statement input
+// before a query ever reaches a driver. It is deliberately not a full SQL
+// parser - just enough scanning to find the leading statement keyword, spot
+// a trailing second statement, and flag a handful of known
+// write-through-read bypasses.
+package classifier
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Category is the coarse class of statement a query belongs to.
+type Category string
+
+const (
+	CategoryRead    Category = "read"
+	CategoryWrite   Category = "write"
+	CategoryDDL     Category = "ddl"
+	CategoryUnknown Category = "unknown"
+)
+
+// leadKeywordCategories maps the first statement keyword to its category.
+var leadKeywordCategories = map[string]Category{
+	"SELECT":   CategoryRead,
+	"SHOW":     CategoryRead,
+	"DESCRIBE": CategoryRead,
+	"DESC":     CategoryRead,
+	"EXPLAIN":  CategoryRead,
+	"WITH":     CategoryRead,
+	"INSERT":   CategoryWrite,
+	"UPDATE":   CategoryWrite,
+	"DELETE":   CategoryWrite,
+	"MERGE":    CategoryWrite,
+	"CREATE":   CategoryDDL,
+	"DROP":     CategoryDDL,
+	"ALTER":    CategoryDDL,
+	"TRUNCATE": CategoryDDL,
+	"GRANT":    CategoryDDL,
+}
+
+// cteWriteKeywords are statement keywords that, if found as the lead of a
+// CTE body (`name AS (KEYWORD ...)`), make an otherwise read-shaped
+// `WITH ... SELECT` query actually perform a write - Postgres's writable
+// CTE extension.
+var cteWriteKeywords = []string{"INSERT", "UPDATE", "DELETE"}
+
+// Classification is the result of classifying a query.
+type Classification struct {
+	// Category is the coarse class the query was assigned to.
+	Category Category `json:"category"`
+	// Keyword is the leading statement keyword that drove the
+	// classification (e.g. "SELECT", "WITH").
+	Keyword string `json:"keyword,omitempty"`
+	// Bypass names the specific evasion pattern detected, if any
+	// (e.g. "writable_cte", "select_into_outfile").
+	Bypass string `json:"bypass,omitempty"`
+}
+
+// Classify inspects query's leading statement keyword and returns its
+// Classification. It returns an error if query contains a second statement
+// after the first, since that's rejected outright rather than classified.
+func Classify(query string) (*Classification, error) {
+	if err := rejectTrailingStatement(query); err != nil {
+		return nil, err
+	}
+
+	keyword := firstKeyword(query)
+	upper := strings.ToUpper(query)
+
+	if hasSelectIntoOutfile(upper) {
+		return &Classification{Category: CategoryWrite, Keyword: keyword, Bypass: "select_into_outfile"}, nil
+	}
+
+	if keyword == "WITH" {
+		if kw := writableCTEKeyword(upper); kw != "" {
+			return &Classification{Category: CategoryWrite, Keyword: keyword, Bypass: "writable_cte"}, nil
+		}
+	}
+
+	category, ok := leadKeywordCategories[keyword]
+	if !ok {
+		category = CategoryUnknown
+	}
+	return &Classification{Category: category, Keyword: keyword}, nil
+}
+
+// firstKeyword returns the upper-cased first word of query, skipping any
+// leading whitespace and `--`/`/* */` comments.
+func firstKeyword(query string) string {
+	i := 0
+	for i < len(query) {
+		switch {
+		case unicode.IsSpace(rune(query[i])):
+			i++
+		case strings.HasPrefix(query[i:], "--"):
+			if nl := strings.IndexByte(query[i:], '\n'); nl >= 0 {
+				i += nl + 1
+			} else {
+				return ""
+			}
+		case strings.HasPrefix(query[i:], "/*"):
+			if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				return ""
+			}
+		default:
+			j := i
+			for j < len(query) && (unicode.IsLetter(rune(query[j])) || query[j] == '_') {
+				j++
+			}
+			return strings.ToUpper(query[i:j])
+		}
+	}
+	return ""
+}
+
+// hasSelectIntoOutfile reports whether upper contains MySQL's
+// `SELECT ... INTO OUTFILE`/`INTO DUMPFILE` construct, which writes the
+// result set to a file on the server and would otherwise slip through as a
+// read because the statement starts with SELECT.
+func hasSelectIntoOutfile(upper string) bool {
+	return strings.Contains(upper, "INTO OUTFILE") || strings.Contains(upper, "INTO DUMPFILE")
+}
+
+// writableCTEKeyword scans upper for `AS (KEYWORD ...)` following a CTE
+// name and returns the first write keyword found as a CTE body, or "" if
+// none is found.
+func writableCTEKeyword(upper string) string {
+	searchFrom := 0
+	for {
+		i := strings.Index(upper[searchFrom:], "AS")
+		if i == -1 {
+			return ""
+		}
+		i += searchFrom
+		searchFrom = i + 2
+
+		rest := strings.TrimLeft(upper[i+2:], " \t\r\n")
+		if !strings.HasPrefix(rest, "(") {
+			continue
+		}
+		rest = strings.TrimLeft(rest[1:], " \t\r\n")
+		for _, kw := range cteWriteKeywords {
+			if strings.HasPrefix(rest, kw) {
+				return kw
+			}
+		}
+	}
+}
+
+// rejectTrailingStatement scans query for a `;` outside of a string,
+// quoted identifier, or comment, and errors if any non-whitespace,
+// non-comment content follows it - i.e. a second statement.
+func rejectTrailingStatement(query string) error {
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+		case '-':
+			if i+1 < len(query) && query[i+1] == '-' {
+				if nl := strings.IndexByte(query[i:], '\n'); nl >= 0 {
+					i += nl
+				} else {
+					return nil
+				}
+			}
+		case '/':
+			if i+1 < len(query) && query[i+1] == '*' {
+				if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+					i += end + 3
+				} else {
+					return nil
+				}
+			}
+		case ';':
+			if hasMeaningfulContent(query[i+1:]) {
+				return fmt.Errorf("multiple statements are not allowed")
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// hasMeaningfulContent reports whether rest contains anything other than
+// whitespace and comments.
+func hasMeaningfulContent(rest string) bool {
+	return firstKeyword(rest) != "" || strings.TrimSpace(stripComments(rest)) != ""
+}
+
+// stripComments removes `--` and `/* */` comments from s so
+// hasMeaningfulContent can fall back to a plain whitespace check for
+// trailing content that isn't a recognized keyword (e.g. stray
+// punctuation).
+func stripComments(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], "--") {
+			if nl := strings.IndexByte(s[i:], '\n'); nl >= 0 {
+				i += nl
+				continue
+			}
+			break
+		}
+		if strings.HasPrefix(s[i:], "/*") {
+			if end := strings.Index(s[i+2:], "*/"); end >= 0 {
+				i += end + 3
+				continue
+			}
+			break
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
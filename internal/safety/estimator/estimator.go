@@ -0,0 +1,46 @@
+// Package estimator holds the cost/cardinality estimate produced by a
+// driver's EXPLAIN-based guardrail check, run before a query executes.
+package estimator
+
+// Estimate summarizes a driver's best guess at how expensive a query will
+// be to run, derived from its EXPLAIN/EXPLAIN ESTIMATE output.
+type Estimate struct {
+	EstimatedRows  uint64  `json:"estimatedRows"`
+	EstimatedBytes uint64  `json:"estimatedBytes"`
+	EstimatedCost  float64 `json:"estimatedCost,omitempty"`
+	FullTableScan  bool    `json:"fullTableScan,omitempty"`
+}
+
+// Clamp treats a zero estimate as "at least 1" rather than "free": plan
+// estimates can round to zero on empty or stale statistics, which would
+// otherwise let an unbounded query slip past every threshold.
+func Clamp(n uint64) uint64 {
+	if n == 0 {
+		return 1
+	}
+	return n
+}
+
+// Exceeds reports whether the estimate breaches any of the supplied
+// thresholds. A zero threshold means "no limit" for that dimension.
+// FullTableScan has no configurable threshold - a driver only sets it once
+// it has positively identified a scan with no primary-key filter, so any
+// true value always counts as exceeding the guardrail.
+func (e *Estimate) Exceeds(maxRows, maxBytes uint64, maxCost float64) bool {
+	if e == nil {
+		return false
+	}
+	if e.FullTableScan {
+		return true
+	}
+	if maxRows > 0 && Clamp(e.EstimatedRows) > maxRows {
+		return true
+	}
+	if maxBytes > 0 && Clamp(e.EstimatedBytes) > maxBytes {
+		return true
+	}
+	if maxCost > 0 && e.EstimatedCost > maxCost {
+		return true
+	}
+	return false
+}
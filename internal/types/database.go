@@ -15,6 +15,8 @@ type Table struct {
 	Type        string `json:"type"` // "table", "view", "materialized_view"
 	Description string `json:"description,omitempty"`
 	RowCount    *int64 `json:"rowCount,omitempty"`
+	Engine      string `json:"engine,omitempty"`    // MySQL storage engine (e.g. "InnoDB")
+	Collation   string `json:"collation,omitempty"` // MySQL table collation
 }
 
 // Column represents a database column
@@ -51,13 +53,39 @@ type Index struct {
 
 // QueryResult represents the result of running a SQL query
 type QueryResult struct {
-	Columns         []string        `json:"columns"`
+	Columns []string `json:"columns"`
+	// ColumnTypes gives each column's database-reported type name (e.g.
+	// "integer", "geometry"), parallel to Columns. Only populated by
+	// drivers that can describe a statement's result ahead of execution;
+	// nil otherwise.
+	ColumnTypes     []string        `json:"columnTypes,omitempty"`
 	Rows            [][]interface{} `json:"rows"`
 	RowCount        int             `json:"rowCount"`
 	ExecutionTimeMs int64           `json:"executionTimeMs"`
 	Query           string          `json:"query"`
 }
 
+// GeoValue is the structured encoding PostgreSQLDriver.RunSQL produces for
+// a PostGIS geometry/geography column, in place of the raw WKB bytes pgx
+// would otherwise return for a type it has no codec for.
+type GeoValue struct {
+	WKT     string `json:"wkt"`
+	GeoJSON string `json:"geojson"`
+	SRID    int    `json:"srid"`
+	// BBox is [minX, minY, maxX, maxY], omitted if the envelope couldn't
+	// be computed (e.g. an empty or NULL geometry).
+	BBox []float64 `json:"bbox,omitempty"`
+}
+
+// SpatialColumn describes one PostGIS geometry/geography column of a
+// table, as reported by Manager.DescribeSpatial.
+type SpatialColumn struct {
+	Column       string `json:"column"`
+	SRID         int    `json:"srid"`
+	GeometryType string `json:"geometryType"`
+	HasGistIndex bool   `json:"hasGistIndex"`
+}
+
 // ExplainResult represents the result of explaining a SQL query
 type ExplainResult struct {
 	Query           string                 `json:"query"`